@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/mokuhyo-driven-test/api/internal/ai"
+	"github.com/mokuhyo-driven-test/api/internal/events"
 	"github.com/mokuhyo-driven-test/api/internal/handler"
 	"github.com/mokuhyo-driven-test/api/internal/repository"
 	postgresRepo "github.com/mokuhyo-driven-test/api/internal/repository/postgres"
@@ -59,16 +62,30 @@ func main() {
 	// Google OAuth設定
 	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
 	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+	// フロントエンドのポートを環境変数から取得（デフォルトは3000）
+	frontendPort := os.Getenv("FRONTEND_PORT")
+	if frontendPort == "" {
+		frontendPort = "3000"
+	}
 	googleRedirectURL := os.Getenv("GOOGLE_REDIRECT_URL")
 	if googleRedirectURL == "" {
-		// フロントエンドのポートを環境変数から取得（デフォルトは3000）
-		frontendPort := os.Getenv("FRONTEND_PORT")
-		if frontendPort == "" {
-			frontendPort = "3000"
-		}
 		googleRedirectURL = fmt.Sprintf("http://localhost:%s", frontendPort)
 	}
 
+	// CORS と WebSocket を許可するオリジン（カンマ区切り）
+	// 未設定の場合、CORS は従来どおりすべてのオリジンを許可し、WebSocket だけローカルのフロントエンドに限る
+	var allowedOrigins []string
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			allowedOrigins = append(allowedOrigins, origin)
+		}
+	}
+	corsOrigins, websocketOrigins := allowedOrigins, allowedOrigins
+	if len(allowedOrigins) == 0 {
+		corsOrigins = []string{"*"}
+		websocketOrigins = []string{fmt.Sprintf("http://localhost:%s", frontendPort)}
+	}
+
 	if googleClientID == "" || googleClientSecret == "" {
 		log.Fatal("GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET are required")
 	}
@@ -82,6 +99,16 @@ func main() {
 	var edgeRepo repository.EdgeRepository
 	var settingsRepo repository.SettingsRepository
 	var userRepo repository.UserRepository
+	var snapshotRepo repository.SnapshotRepository
+	var memberRepo repository.MemberRepository
+	var shareRepo repository.ShareLinkRepository
+	var feedRepo repository.CalendarFeedRepository
+	var searchRepo repository.SearchRepository
+	var tagRepo repository.TagRepository
+	var auditRepo repository.AuditRepository
+	var revisionRepo repository.NodeRevisionRepository
+	var templateRepo repository.TemplateRepository
+	var uow repository.UnitOfWork
 
 	switch dbType {
 	case "supabase":
@@ -90,62 +117,150 @@ func main() {
 		edgeRepo = supabaseRepo.NewEdgeRepository(db)
 		settingsRepo = supabaseRepo.NewSettingsRepository(db)
 		userRepo = supabaseRepo.NewUserRepository(db)
+		snapshotRepo = supabaseRepo.NewSnapshotRepository(db)
+		memberRepo = supabaseRepo.NewMemberRepository(db)
+		shareRepo = supabaseRepo.NewShareLinkRepository(db)
+		feedRepo = supabaseRepo.NewCalendarFeedRepository(db)
+		searchRepo = supabaseRepo.NewSearchRepository(db)
+		tagRepo = supabaseRepo.NewTagRepository(db)
+		auditRepo = supabaseRepo.NewAuditRepository(db)
+		revisionRepo = supabaseRepo.NewNodeRevisionRepository(db)
+		templateRepo = supabaseRepo.NewTemplateRepository(db)
+		uow = supabaseRepo.NewUnitOfWork(db)
 	case "local", "postgres":
 		projectRepo = postgresRepo.NewProjectRepository(db)
 		nodeRepo = postgresRepo.NewNodeRepository(db)
 		edgeRepo = postgresRepo.NewEdgeRepository(db)
 		settingsRepo = postgresRepo.NewSettingsRepository(db)
 		userRepo = postgresRepo.NewUserRepository(db)
+		snapshotRepo = postgresRepo.NewSnapshotRepository(db)
+		memberRepo = postgresRepo.NewMemberRepository(db)
+		shareRepo = postgresRepo.NewShareLinkRepository(db)
+		feedRepo = postgresRepo.NewCalendarFeedRepository(db)
+		searchRepo = postgresRepo.NewSearchRepository(db)
+		tagRepo = postgresRepo.NewTagRepository(db)
+		auditRepo = postgresRepo.NewAuditRepository(db)
+		revisionRepo = postgresRepo.NewNodeRevisionRepository(db)
+		templateRepo = postgresRepo.NewTemplateRepository(db)
+		uow = postgresRepo.NewUnitOfWork(db)
 	}
 
+	// イベント配信（EVENTS_FANOUT=postgres で LISTEN/NOTIFY によりインスタンス間で中継）
+	var fanOut events.FanOut
+	switch eventsFanOut := strings.ToLower(os.Getenv("EVENTS_FANOUT")); eventsFanOut {
+	case "", "local":
+	case "postgres":
+		fanOut = events.NewPostgresFanOut(db, dbURL)
+	default:
+		log.Fatalf("Invalid EVENTS_FANOUT: %s. Valid values are 'local' or 'postgres'", eventsFanOut)
+	}
+	eventBroker := events.NewBroker(fanOut)
+	go func() {
+		if err := eventBroker.Run(context.Background()); err != nil {
+			log.Printf("Event fan-out stopped: %v", err)
+		}
+	}()
+
 	// Services
 	authService := service.NewAuthService(userRepo)
-	projectService := service.NewProjectService(projectRepo, nodeRepo, edgeRepo)
+	auditService := service.NewAuditService(auditRepo)
+	projectService := service.NewProjectService(projectRepo, memberRepo, nodeRepo, edgeRepo, uow, eventBroker)
 
+	// AI_PROVIDER で質問生成のプロバイダーを選択（gemini / openai / ollama / fake）
+	// 未設定の場合は GEMINI_API_KEY があれば gemini を使用
 	var questionGenerator ai.QuestionGenerator
-	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
-	geminiModel := os.Getenv("GEMINI_MODEL")
-	if geminiAPIKey != "" {
-		generator, err := ai.NewGeminiQuestionGenerator(context.Background(), geminiAPIKey, geminiModel)
+	aiProvider := strings.ToLower(os.Getenv("AI_PROVIDER"))
+	aiConfig := ai.Config{
+		APIKey:  os.Getenv("AI_API_KEY"),
+		Model:   os.Getenv("AI_MODEL"),
+		BaseURL: os.Getenv("AI_BASE_URL"),
+	}
+	if aiProvider == "" && os.Getenv("GEMINI_API_KEY") != "" {
+		aiProvider = "gemini"
+	}
+	if aiProvider == "gemini" {
+		if aiConfig.APIKey == "" {
+			aiConfig.APIKey = os.Getenv("GEMINI_API_KEY")
+		}
+		if aiConfig.Model == "" {
+			aiConfig.Model = os.Getenv("GEMINI_MODEL")
+		}
+	}
+	if aiProvider != "" {
+		generator, err := ai.NewQuestionGenerator(context.Background(), aiProvider, aiConfig)
 		if err != nil {
-			log.Printf("Gemini client init failed: %v", err)
+			log.Printf("AI provider %s init failed: %v", aiProvider, err)
 		} else {
+			log.Printf("Using AI provider: %s", aiProvider)
 			questionGenerator = generator
 			defer generator.Close()
 		}
 	} else {
-		log.Println("GEMINI_API_KEY is not set, using fallback question generation")
+		log.Println("AI_PROVIDER is not set, using fallback question generation")
 	}
 
-	nodeService := service.NewNodeService(nodeRepo, edgeRepo, questionGenerator)
-	edgeService := service.NewEdgeService(edgeRepo)
+	nodeService := service.NewNodeService(nodeRepo, edgeRepo, questionGenerator, eventBroker, uow)
+	edgeService := service.NewEdgeService(edgeRepo, uow, eventBroker)
+	historyService := service.NewHistoryService(uow, eventBroker)
+	revisionService := service.NewRevisionService(nodeRepo, revisionRepo, uow, eventBroker)
 	settingsService := service.NewSettingsService(settingsRepo)
+	memberService := service.NewMemberService(memberRepo, userRepo, uow)
+	shareService := service.NewShareService(shareRepo, uow)
+	agendaService := service.NewAgendaService(nodeRepo, feedRepo)
+	searchService := service.NewSearchService(searchRepo)
+	tagService := service.NewTagService(tagRepo, uow, eventBroker)
+	snapshotService := service.NewSnapshotService(projectRepo, snapshotRepo, uow, eventBroker)
+	templateService := service.NewTemplateService(templateRepo, uow)
+
+	// ゴミ箱の保持期間（日数、デフォルト30日）
+	trashRetentionDays := 30
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 0 {
+			log.Fatalf("Invalid TRASH_RETENTION_DAYS: %s", v)
+		}
+		trashRetentionDays = days
+	}
+	trashService := service.NewTrashService(nodeRepo, uow, eventBroker, time.Duration(trashRetentionDays)*24*time.Hour)
+	go trashService.RunPurgeLoop(context.Background(), time.Hour)
 
 	// Handlers
-	authHandler := handler.NewAuthHandler(authService, googleOAuthConfig)
+	authHandler := handler.NewAuthHandler(authService, auditService, googleOAuthConfig)
 	meHandler := handler.NewMeHandler(settingsService)
-	projectHandler := handler.NewProjectHandler(projectService)
-	nodeHandler := handler.NewNodeHandler(nodeService, projectService)
+	projectHandler := handler.NewProjectHandler(projectService, auditService)
+	nodeHandler := handler.NewNodeHandler(nodeService, projectService, auditService)
 	edgeHandler := handler.NewEdgeHandler(edgeService, projectService)
-	settingsHandler := handler.NewSettingsHandler(settingsService)
+	historyHandler := handler.NewHistoryHandler(historyService, projectService)
+	revisionHandler := handler.NewRevisionHandler(revisionService, projectService)
+	settingsHandler := handler.NewSettingsHandler(settingsService, auditService)
+	snapshotHandler := handler.NewSnapshotHandler(snapshotService, projectService)
+	trashHandler := handler.NewTrashHandler(trashService, projectService, auditService)
+	eventsHandler := handler.NewEventsHandler(nodeService, projectService, websocketOrigins)
+	memberHandler := handler.NewMemberHandler(memberService, projectService, auditService)
+	shareHandler := handler.NewShareHandler(shareService, projectService, auditService)
+	agendaHandler := handler.NewAgendaHandler(agendaService, auditService)
+	searchHandler := handler.NewSearchHandler(searchService)
+	tagHandler := handler.NewTagHandler(tagService, projectService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	templateHandler := handler.NewTemplateHandler(templateService, projectService, auditService)
 
 	// Router setup
 	r := gin.Default()
 
-	// CORS middleware (adjust for production)
-	r.Use(func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
+	// X-Forwarded-For を信頼するプロキシ（カンマ区切りのIPかCIDR）。未設定なら信頼せず接続元のIPを使う
+	// 監査ログのIPアドレスをクライアントが偽装できないよう、すべてのプロキシを信頼する gin の既定値は使わない
+	var trustedProxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			trustedProxies = append(trustedProxies, proxy)
 		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 
-		c.Next()
-	})
+	// CORS middleware
+	r.Use(handler.CORS(corsOrigins))
 
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
@@ -158,8 +273,15 @@ func main() {
 		// Public auth routes
 		v1.POST("/auth/google", authHandler.HandleGoogleAuth)
 
+		// Public share links
+		v1.GET("/shared/:token/tree", shareHandler.GetSharedTree)
+
+		// Public calendar feed
+		v1.GET("/calendar/:token/agenda.ics", agendaHandler.GetCalendarFeed)
+
 		// Auth required routes
 		authRequired := v1.Group("")
+		authRequired.Use(handler.WebSocketTokenFromProtocol)
 		// Google認証ミドルウェアを使用
 		authRequired.Use(func(c *gin.Context) {
 			// ミドルウェア内でユーザーIDを取得する関数
@@ -181,23 +303,87 @@ func main() {
 			// Projects
 			authRequired.POST("/projects", projectHandler.CreateProject)
 			authRequired.GET("/projects", projectHandler.ListProjects)
+			authRequired.POST("/projects/import", projectHandler.ImportProject)
 			authRequired.GET("/projects/:projectId", projectHandler.GetProject)
 			authRequired.PATCH("/projects/:projectId", projectHandler.UpdateProject)
 			authRequired.GET("/projects/:projectId/tree", projectHandler.GetTree)
-			authRequired.POST("/projects/:projectId/save", projectHandler.SaveProject)
+			authRequired.GET("/projects/:projectId/export", projectHandler.ExportProject)
+			authRequired.POST("/projects/:projectId/duplicate", projectHandler.DuplicateProject)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/extract", projectHandler.ExtractSubtree)
+			authRequired.GET("/projects/:projectId/events", eventsHandler.StreamProjectEvents)
+			authRequired.GET("/projects/:projectId/ws", eventsHandler.ProjectSocket)
+			authRequired.POST("/projects/:projectId/save", snapshotHandler.SaveProject)
+
+			// Members
+			authRequired.GET("/projects/:projectId/members", memberHandler.ListMembers)
+			authRequired.POST("/projects/:projectId/members", memberHandler.InviteMember)
+			authRequired.PATCH("/projects/:projectId/members/:userId", memberHandler.UpdateMember)
+			authRequired.DELETE("/projects/:projectId/members/:userId", memberHandler.RemoveMember)
+
+			// Share links
+			authRequired.GET("/projects/:projectId/share-links", shareHandler.ListShareLinks)
+			authRequired.POST("/projects/:projectId/share-links", shareHandler.CreateShareLink)
+			authRequired.DELETE("/projects/:projectId/share-links/:shareLinkId", shareHandler.RevokeShareLink)
+
+			// Tags
+			authRequired.GET("/projects/:projectId/tags", tagHandler.ListTags)
+			authRequired.POST("/projects/:projectId/tags", tagHandler.CreateTag)
+			authRequired.PATCH("/projects/:projectId/tags/:tagId", tagHandler.UpdateTag)
+			authRequired.DELETE("/projects/:projectId/tags/:tagId", tagHandler.DeleteTag)
+			authRequired.PUT("/projects/:projectId/nodes/:nodeId/tags", tagHandler.SetNodeTags)
+
+			// Snapshots
+			authRequired.GET("/projects/:projectId/snapshots", snapshotHandler.ListSnapshots)
+			authRequired.GET("/projects/:projectId/snapshots/:version", snapshotHandler.GetSnapshot)
+			authRequired.GET("/projects/:projectId/snapshots/:version/diff", snapshotHandler.DiffSnapshots)
+			authRequired.POST("/projects/:projectId/snapshots/:version/restore", snapshotHandler.RestoreSnapshot)
 
 			// Nodes
 			authRequired.POST("/projects/:projectId/nodes", nodeHandler.CreateNode)
 			authRequired.PATCH("/projects/:projectId/nodes/:nodeId", nodeHandler.UpdateNode)
 			authRequired.DELETE("/projects/:projectId/nodes/:nodeId", nodeHandler.DeleteNode)
+			authRequired.PUT("/projects/:projectId/nodes/:nodeId/status", nodeHandler.UpdateNodeStatus)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/move", nodeHandler.MoveNode)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/restore", trashHandler.RestoreNode)
+			authRequired.GET("/projects/:projectId/nodes/:nodeId/history", revisionHandler.GetNodeHistory)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/revert/:revisionId", revisionHandler.RevertNode)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/question-suggestions", nodeHandler.SuggestQuestions)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/expand", nodeHandler.ExpandNode)
+			authRequired.POST("/projects/:projectId/nodes/:nodeId/expand/accept", nodeHandler.AcceptDraft)
+
+			// Trash
+			authRequired.GET("/projects/:projectId/trash", trashHandler.ListTrash)
+			authRequired.DELETE("/projects/:projectId/trash", trashHandler.PurgeTrash)
 
 			// Edges
 			authRequired.PATCH("/projects/:projectId/edges/:edgeId", edgeHandler.UpdateEdge)
 			authRequired.POST("/projects/:projectId/reorder", edgeHandler.Reorder)
 
+			// History
+			authRequired.POST("/projects/:projectId/undo", historyHandler.Undo)
+			authRequired.POST("/projects/:projectId/redo", historyHandler.Redo)
+
+			// Templates
+			authRequired.GET("/templates", templateHandler.ListTemplates)
+			authRequired.POST("/templates", templateHandler.CreateTemplate)
+			authRequired.GET("/templates/:templateId", templateHandler.GetTemplate)
+			authRequired.DELETE("/templates/:templateId", templateHandler.DeleteTemplate)
+			authRequired.POST("/templates/:templateId/projects", templateHandler.CreateProjectFromTemplate)
+
+			// Search
+			authRequired.GET("/search", searchHandler.Search)
+
+			// Agenda
+			authRequired.GET("/agenda", agendaHandler.GetAgenda)
+			authRequired.POST("/agenda/feed", agendaHandler.CreateCalendarFeed)
+			authRequired.DELETE("/agenda/feed", agendaHandler.RevokeCalendarFeed)
+
 			// Settings
 			authRequired.GET("/settings", settingsHandler.GetSettings)
 			authRequired.PATCH("/settings", settingsHandler.UpdateSettings)
+
+			// Audit
+			authRequired.GET("/audit", auditHandler.ListAuditEvents)
 		}
 	}
 
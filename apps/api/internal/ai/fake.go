@@ -0,0 +1,67 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+)
+
+// FakeQuestionGenerator はネットワークを使わずに決定的な質問を返す実装です
+// 同じプロンプトには常に同じ質問を返すため、ローカル開発や動作確認に使えます
+type FakeQuestionGenerator struct {
+	questions []string
+	children  []fakeChild
+}
+
+type fakeChild struct {
+	Content  string `json:"content"`
+	Question string `json:"question"`
+	Relation string `json:"relation"`
+}
+
+func NewFakeQuestionGenerator() *FakeQuestionGenerator {
+	return &FakeQuestionGenerator{
+		questions: []string{
+			"それはなぜ大切ですか？",
+			"まず何から始めますか？",
+			"達成したと言える状態は？",
+			"いつまでに実現しますか？",
+			"一番の障害は何ですか？",
+			"誰の助けが必要ですか？",
+		},
+		children: []fakeChild{
+			{Content: "目指す理由をはっきりさせる", Question: "それはなぜ大切ですか？", Relation: "why"},
+			{Content: "最初の一歩を決める", Question: "まず何から始めますか？", Relation: "how"},
+			{Content: "完了の基準を決める", Question: "達成したと言える状態は？", Relation: "what"},
+			{Content: "期限を設定する", Question: "いつまでに実現しますか？", Relation: "concrete"},
+		},
+	}
+}
+
+func (g *FakeQuestionGenerator) Close() error {
+	return nil
+}
+
+func (g *FakeQuestionGenerator) GenerateQuestion(ctx context.Context, prompt string) (string, error) {
+	return g.questions[fakeIndex(prompt, len(g.questions))], nil
+}
+
+// GenerateText は子ノード案として固定の候補を JSON 配列で返します
+func (g *FakeQuestionGenerator) GenerateText(ctx context.Context, prompt string) (string, error) {
+	start := fakeIndex(prompt, len(g.children))
+	children := make([]fakeChild, 0, len(g.children))
+	for i := range g.children {
+		children = append(children, g.children[(start+i)%len(g.children)])
+	}
+	data, err := json.Marshal(children)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func fakeIndex(seed string, modulo int) int {
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(seed))
+	return int(hasher.Sum32() % uint32(modulo))
+}
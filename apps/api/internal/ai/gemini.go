@@ -14,6 +14,12 @@ type QuestionGenerator interface {
 	Close() error
 }
 
+// TextGenerator は複数行の自由形式テキストを生成できるプロバイダーが実装するインターフェースです
+// 子ノード案のように、1行の質問より長い出力が必要な場合に使用します
+type TextGenerator interface {
+	GenerateText(ctx context.Context, prompt string) (string, error)
+}
+
 type GeminiQuestionGenerator struct {
 	client *genai.Client
 	model  string
@@ -46,15 +52,28 @@ func (g *GeminiQuestionGenerator) Close() error {
 }
 
 func (g *GeminiQuestionGenerator) GenerateQuestion(ctx context.Context, prompt string) (string, error) {
+	text, err := g.generate(ctx, prompt, 0.4, 64)
+	if err != nil {
+		return "", err
+	}
+	text = strings.Split(text, "\n")[0]
+	text = strings.TrimSpace(text)
+
+	return text, nil
+}
+
+func (g *GeminiQuestionGenerator) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return g.generate(ctx, prompt, 0.7, 1024)
+}
+
+func (g *GeminiQuestionGenerator) generate(ctx context.Context, prompt string, temperature float32, maxOutputTokens int32) (string, error) {
 	if g == nil || g.client == nil {
 		return "", fmt.Errorf("gemini client is not initialized")
 	}
 
 	model := g.client.GenerativeModel(g.model)
-	temp := float32(0.4)
-	model.Temperature = &temp
-	maxTokens := int32(64)
-	model.MaxOutputTokens = &maxTokens
+	model.Temperature = &temperature
+	model.MaxOutputTokens = &maxOutputTokens
 
 	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
 	if err != nil {
@@ -66,9 +85,6 @@ func (g *GeminiQuestionGenerator) GenerateQuestion(ctx context.Context, prompt s
 	if text == "" {
 		return "", fmt.Errorf("empty response from gemini")
 	}
-	text = strings.Split(text, "\n")[0]
-	text = strings.TrimSpace(text)
-
 	return text, nil
 }
 
@@ -0,0 +1,125 @@
+package ai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOllamaBaseURL = "http://localhost:11434/v1"
+	defaultOpenAIModel   = "gpt-4o-mini"
+)
+
+// OpenAIQuestionGenerator は OpenAI 互換の Chat Completions API を使う実装です
+// Ollama や llama.cpp の server など、互換エンドポイントを持つローカルサーバーにも接続できます
+type OpenAIQuestionGenerator struct {
+	baseURL    string
+	apiKey     string
+	model      string
+	httpClient *http.Client
+}
+
+func NewOpenAIQuestionGenerator(baseURL, apiKey, model string) (*OpenAIQuestionGenerator, error) {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	if strings.TrimSpace(model) == "" {
+		model = defaultOpenAIModel
+	}
+	return &OpenAIQuestionGenerator{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (g *OpenAIQuestionGenerator) Close() error {
+	return nil
+}
+
+type chatCompletionRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature float32       `json:"temperature"`
+	MaxTokens   int           `json:"max_tokens"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type chatCompletionResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func (g *OpenAIQuestionGenerator) GenerateQuestion(ctx context.Context, prompt string) (string, error) {
+	text, err := g.complete(ctx, prompt, 0.4, 64)
+	if err != nil {
+		return "", err
+	}
+	text = strings.Split(text, "\n")[0]
+	text = strings.TrimSpace(text)
+
+	return text, nil
+}
+
+func (g *OpenAIQuestionGenerator) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return g.complete(ctx, prompt, 0.7, 1024)
+}
+
+func (g *OpenAIQuestionGenerator) complete(ctx context.Context, prompt string, temperature float32, maxTokens int) (string, error) {
+	body, err := json.Marshal(chatCompletionRequest{
+		Model:       g.model,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: temperature,
+		MaxTokens:   maxTokens,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.apiKey)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call chat completions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("chat completions returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var completion chatCompletionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return "", fmt.Errorf("failed to decode chat completions response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", fmt.Errorf("empty response from chat completions")
+	}
+
+	text := strings.TrimSpace(completion.Choices[0].Message.Content)
+	if text == "" {
+		return "", fmt.Errorf("empty response from chat completions")
+	}
+	return text, nil
+}
@@ -0,0 +1,71 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Config は質問生成プロバイダーの接続設定です
+// 各プロバイダーは必要な項目だけを参照します
+type Config struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+}
+
+// ProviderFactory は設定から QuestionGenerator を作成する関数です
+type ProviderFactory func(ctx context.Context, cfg Config) (QuestionGenerator, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]ProviderFactory{
+		"gemini": func(ctx context.Context, cfg Config) (QuestionGenerator, error) {
+			return NewGeminiQuestionGenerator(ctx, cfg.APIKey, cfg.Model)
+		},
+		"openai": func(ctx context.Context, cfg Config) (QuestionGenerator, error) {
+			return NewOpenAIQuestionGenerator(cfg.BaseURL, cfg.APIKey, cfg.Model)
+		},
+		"ollama": func(ctx context.Context, cfg Config) (QuestionGenerator, error) {
+			if strings.TrimSpace(cfg.BaseURL) == "" {
+				cfg.BaseURL = defaultOllamaBaseURL
+			}
+			return NewOpenAIQuestionGenerator(cfg.BaseURL, cfg.APIKey, cfg.Model)
+		},
+		"fake": func(ctx context.Context, cfg Config) (QuestionGenerator, error) {
+			return NewFakeQuestionGenerator(), nil
+		},
+	}
+)
+
+// Register はプロバイダーを名前で登録します（同名の登録は上書きされます）
+func Register(name string, factory ProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[strings.ToLower(name)] = factory
+}
+
+// Providers は登録済みのプロバイダー名を返します
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewQuestionGenerator は名前で選択したプロバイダーの QuestionGenerator を作成します
+func NewQuestionGenerator(ctx context.Context, provider string, cfg Config) (QuestionGenerator, error) {
+	providersMu.RLock()
+	factory, ok := providers[strings.ToLower(provider)]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown ai provider %q (available: %s)", provider, strings.Join(Providers(), ", "))
+	}
+	return factory(ctx, cfg)
+}
@@ -0,0 +1,87 @@
+package events
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// subscriberBuffer は購読者ごとに保持するイベント数です。遅い購読者のためにPublishが詰まらないよう、溢れた分は破棄します
+const subscriberBuffer = 32
+
+// Broker はプロジェクトごとの購読者にイベントを配信するハブです
+// FanOut を設定した場合、イベントは FanOut を経由して全インスタンスの購読者に届きます
+type Broker struct {
+	fanOut FanOut
+
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[chan Event]struct{}
+}
+
+// NewBroker はハブを作成します。fanOut が nil の場合はプロセス内だけで配信します
+func NewBroker(fanOut FanOut) *Broker {
+	return &Broker{
+		fanOut:      fanOut,
+		subscribers: make(map[uuid.UUID]map[chan Event]struct{}),
+	}
+}
+
+// Run は FanOut から届くイベントをローカルの購読者に配信し続けます（FanOut がない場合は何もしません）
+func (b *Broker) Run(ctx context.Context) error {
+	if b.fanOut == nil {
+		return nil
+	}
+	return b.fanOut.Listen(ctx, b.deliver)
+}
+
+// Subscribe はプロジェクトのイベントを受け取るチャネルと、購読を解除する関数を返します
+func (b *Broker) Subscribe(projectID uuid.UUID) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[projectID] == nil {
+		b.subscribers[projectID] = make(map[chan Event]struct{})
+	}
+	b.subscribers[projectID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[projectID], ch)
+			if len(b.subscribers[projectID]) == 0 {
+				delete(b.subscribers, projectID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish はイベントをプロジェクトの購読者全員に送ります
+func (b *Broker) Publish(ctx context.Context, event Event) {
+	if b.fanOut == nil {
+		b.deliver(event)
+		return
+	}
+	if err := b.fanOut.Broadcast(ctx, event); err != nil {
+		// 他のインスタンスに届かなくても、このインスタンスの購読者には届ける
+		log.Printf("failed to broadcast %s event: %v", event.Type, err)
+		b.deliver(event)
+	}
+}
+
+func (b *Broker) deliver(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subscribers[event.ProjectID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
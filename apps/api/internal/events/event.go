@@ -0,0 +1,36 @@
+package events
+
+import "github.com/google/uuid"
+
+// イベントの種類
+const (
+	TypeNodeCreated     = "node.created"
+	TypeNodeUpdated     = "node.updated"
+	TypeNodeDeleted     = "node.deleted"
+	TypeNodeMoved       = "node.moved"
+	TypeNodeRestored    = "node.restored"
+	TypeNodesCreated    = "nodes.created"
+	TypeEdgeUpdated     = "edge.updated"
+	TypeReordered       = "edges.reordered"
+	TypeProjectUpdated  = "project.updated"
+	TypeTreeRestored    = "tree.restored"
+	TypeTagCreated      = "tag.created"
+	TypeTagUpdated      = "tag.updated"
+	TypeTagDeleted      = "tag.deleted"
+	TypeNodeTagsUpdated = "node.tags_updated"
+	TypeOperationUndone = "operation.undone"
+	TypeOperationRedone = "operation.redone"
+	TypeQuestionPending = "question.pending"
+	TypeQuestionReady   = "question.ready"
+)
+
+// Event はプロジェクト単位で配信されるイベントです
+type Event struct {
+	Type      string    `json:"type"`
+	ProjectID uuid.UUID `json:"project_id"`
+	// Revision は変更後のプロジェクトのリビジョンです（不明な場合は0）
+	Revision int64       `json:"revision,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	// Truncated が true の場合、Data は省略されているのでツリーを取得し直す必要があります
+	Truncated bool `json:"truncated,omitempty"`
+}
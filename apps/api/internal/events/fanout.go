@@ -0,0 +1,12 @@
+package events
+
+import "context"
+
+// FanOut は複数のサーバーインスタンス間でイベントを中継する仕組みです
+type FanOut interface {
+	// Broadcast はイベントを全インスタンス（自身を含む）に送ります
+	Broadcast(ctx context.Context, event Event) error
+
+	// Listen は届いたイベントを ctx が終了するまで deliver に渡し続けます
+	Listen(ctx context.Context, deliver func(Event)) error
+}
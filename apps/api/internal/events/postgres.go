@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	notifyChannel = "mokuhyo_events"
+	// maxNotifyPayload は NOTIFY のペイロード上限（8000バイト）に余裕を持たせた値です
+	maxNotifyPayload = 7800
+
+	listenRetryInterval = 5 * time.Second
+)
+
+// PostgresFanOut は PostgreSQL の LISTEN/NOTIFY でイベントを中継します
+// LISTEN は専用の接続を使うため、トランザクションモードのコネクションプーラー経由では動作しません
+type PostgresFanOut struct {
+	db         execer
+	connString string
+}
+
+// execer は NOTIFY の送信に使う接続です（アプリケーションの DBInterface を渡します）
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+func NewPostgresFanOut(db execer, connString string) *PostgresFanOut {
+	return &PostgresFanOut{db: db, connString: connString}
+}
+
+func (f *PostgresFanOut) Broadcast(ctx context.Context, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	if len(payload) > maxNotifyPayload {
+		event.Data = nil
+		event.Truncated = true
+		if payload, err = json.Marshal(event); err != nil {
+			return fmt.Errorf("failed to encode event: %w", err)
+		}
+	}
+
+	if _, err := f.db.Exec(ctx, "SELECT pg_notify($1, $2)", notifyChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+	return nil
+}
+
+// Listen は接続が切れても ctx が終了するまで再接続を続けます
+func (f *PostgresFanOut) Listen(ctx context.Context, deliver func(Event)) error {
+	for {
+		err := f.listen(ctx, deliver)
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Printf("event listener disconnected, retrying: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(listenRetryInterval):
+		}
+	}
+}
+
+func (f *PostgresFanOut) listen(ctx context.Context, deliver func(Event)) error {
+	config, err := pgx.ParseConfig(f.connString)
+	if err != nil {
+		return fmt.Errorf("failed to parse connection string: %w", err)
+	}
+	// IPv4接続を強制（IPv6接続の問題を回避）
+	config.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d := &net.Dialer{}
+		return d.DialContext(ctx, "tcp4", addr)
+	}
+
+	conn, err := pgx.ConnectConfig(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Printf("failed to decode event: %v", err)
+			continue
+		}
+		deliver(event)
+	}
+}
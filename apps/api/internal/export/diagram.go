@@ -0,0 +1,83 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// Mermaid はツリーを Mermaid の flowchart として出力します
+// エッジには関係（why / how / what / concrete / カスタムラベル）をラベルとして付与します
+func Mermaid(tree model.TreeResponse) []byte {
+	var builder strings.Builder
+	builder.WriteString("flowchart TD\n")
+
+	walkDiagram(BuildOutline(tree.Nodes, tree.Edges), func(id string, node *OutlineNode) {
+		fmt.Fprintf(&builder, "  %s[\"%s\"]\n", id, escapeMermaid(nodeText(node.Node)))
+	}, func(parentID, childID string, edge model.Edge) {
+		if label := relationText(edge); label != "" {
+			fmt.Fprintf(&builder, "  %s -->|\"%s\"| %s\n", parentID, escapeMermaid(label), childID)
+			return
+		}
+		fmt.Fprintf(&builder, "  %s --> %s\n", parentID, childID)
+	})
+	return []byte(builder.String())
+}
+
+// DOT はツリーを Graphviz の DOT 形式で出力します
+func DOT(tree model.TreeResponse) []byte {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "digraph \"%s\" {\n", escapeDOT(singleLine(tree.Project.Title)))
+	builder.WriteString("  graph [charset=\"UTF-8\"];\n")
+	builder.WriteString("  node [shape=box, style=rounded, fontname=\"Noto Sans CJK JP\"];\n")
+	builder.WriteString("  edge [fontname=\"Noto Sans CJK JP\"];\n")
+
+	walkDiagram(BuildOutline(tree.Nodes, tree.Edges), func(id string, node *OutlineNode) {
+		fmt.Fprintf(&builder, "  %s [label=\"%s\"];\n", id, escapeDOT(nodeText(node.Node)))
+	}, func(parentID, childID string, edge model.Edge) {
+		if label := relationText(edge); label != "" {
+			fmt.Fprintf(&builder, "  %s -> %s [label=\"%s\"];\n", parentID, childID, escapeDOT(label))
+			return
+		}
+		fmt.Fprintf(&builder, "  %s -> %s;\n", parentID, childID)
+	})
+
+	builder.WriteString("}\n")
+	return []byte(builder.String())
+}
+
+// walkDiagram はツリーを深さ優先でたどり、ノードに n0, n1, ... の識別子を振って各コールバックを呼びます
+func walkDiagram(roots []*OutlineNode, onNode func(id string, node *OutlineNode), onEdge func(parentID, childID string, edge model.Edge)) {
+	next := 0
+	var walk func(node *OutlineNode, parentID string)
+	walk = func(node *OutlineNode, parentID string) {
+		id := fmt.Sprintf("n%d", next)
+		next++
+		onNode(id, node)
+		if parentID != "" {
+			onEdge(parentID, id, node.Edge)
+		}
+		for _, child := range node.Children {
+			walk(child, id)
+		}
+	}
+	for _, root := range roots {
+		walk(root, "")
+	}
+}
+
+// Mermaid のラベルでは " や <> が構文・HTMLとして解釈されるため、エンティティコードに置き換えます
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;", `"`, "#quot;", "<", "#lt;", ">", "#gt;",
+)
+
+func escapeMermaid(text string) string {
+	return mermaidEscaper.Replace(text)
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+func escapeDOT(text string) string {
+	return dotEscaper.Replace(text)
+}
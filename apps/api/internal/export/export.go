@@ -0,0 +1,74 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrInvalidDocument   = errors.New("invalid document")
+)
+
+// Document はエクスポート結果のファイル内容です
+type Document struct {
+	Data        []byte
+	ContentType string
+	Extension   string
+}
+
+// Tree はインポートしたドキュメントから読み取ったプロジェクトの内容です
+// ノードとエッジのIDは未採番で、ツリー構造は Roots からの入れ子で表します
+type Tree struct {
+	Title       string
+	Description *string
+	Roots       []*OutlineNode
+}
+
+// Render はツリーを指定フォーマットで出力します
+func Render(format string, tree model.TreeResponse) (*Document, error) {
+	switch format {
+	case "markdown", "md":
+		return &Document{Data: Markdown(tree), ContentType: "text/markdown; charset=utf-8", Extension: "md"}, nil
+	case "json":
+		data, err := JSON(tree)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Data: data, ContentType: "application/json; charset=utf-8", Extension: "json"}, nil
+	case "opml":
+		data, err := OPML(tree)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Data: data, ContentType: "text/x-opml; charset=utf-8", Extension: "opml"}, nil
+	case "freemind", "mm":
+		data, err := FreeMind(tree)
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Data: data, ContentType: "application/x-freemind; charset=utf-8", Extension: "mm"}, nil
+	case "mermaid":
+		return &Document{Data: Mermaid(tree), ContentType: "text/plain; charset=utf-8", Extension: "mmd"}, nil
+	case "dot":
+		return &Document{Data: DOT(tree), ContentType: "text/vnd.graphviz; charset=utf-8", Extension: "dot"}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// Parse は指定フォーマットのドキュメントを読み込みます
+func Parse(format string, data []byte) (*Tree, error) {
+	switch format {
+	case "json":
+		return ParseJSON(data)
+	case "opml":
+		return ParseOPML(data)
+	case "freemind", "mm":
+		return ParseFreeMind(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
@@ -0,0 +1,281 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func strPtr(s string) *string { return &s }
+
+// sampleTree は根1つ・子2つ・孫1つのツリーです
+// エッジは order_index と異なる順に並べ、出力側で並べ替えられることを確かめます
+func sampleTree() model.TreeResponse {
+	root, first, second, grandchild := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	return model.TreeResponse{
+		Project: model.Project{Title: "英語学習", Description: strPtr("1年で\n話せるようになる")},
+		Nodes: []model.Node{
+			{ID: root, Content: "英語を話せるようになる", Question: strPtr("なぜ話したい？"), Status: model.NodeStatusTodo},
+			{ID: first, Content: "毎日*30分*話す", Status: model.NodeStatusDoing},
+			{ID: second, Content: "海外の友人と\n話したい", Status: model.NodeStatusTodo},
+			{ID: grandchild, Content: "オンライン英会話を予約する", Status: model.NodeStatusDone},
+		},
+		Edges: []model.Edge{
+			{ParentNodeID: &root, ChildNodeID: second, Relation: model.RelationWhy, OrderIndex: 1},
+			{ParentNodeID: nil, ChildNodeID: root, Relation: model.RelationNeutral, OrderIndex: 0},
+			{ParentNodeID: &first, ChildNodeID: grandchild, Relation: model.RelationCustom, RelationLabel: strPtr("手段"), OrderIndex: 0},
+			{ParentNodeID: &root, ChildNodeID: first, Relation: model.RelationHow, RelationLabel: strPtr("習慣"), OrderIndex: 0},
+		},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		tree func() model.TreeResponse
+		want string
+	}{
+		{
+			name: "nested outline with annotations",
+			tree: sampleTree,
+			want: "# 英語学習\n" +
+				"\n> 1年で 話せるようになる\n" +
+				"\n" +
+				"- 英語を話せるようになる （問い: なぜ話したい？）\n" +
+				"  - 毎日\\*30分\\*話す （関係: how(習慣)）\n" +
+				"    - オンライン英会話を予約する （関係: 手段）\n" +
+				"  - 海外の友人と 話したい （関係: why）\n",
+		},
+		{
+			name: "empty project without description",
+			tree: func() model.TreeResponse {
+				return model.TreeResponse{Project: model.Project{Title: "#見出し [仮]"}}
+			},
+			want: "# \\#見出し \\[仮\\]\n\n",
+		},
+		{
+			name: "blank content and blank description",
+			tree: func() model.TreeResponse {
+				id := uuid.New()
+				return model.TreeResponse{
+					Project: model.Project{Title: "空", Description: strPtr("  ")},
+					Nodes:   []model.Node{{ID: id, Content: " \n "}},
+					Edges:   []model.Edge{{ChildNodeID: id, Relation: model.RelationNeutral}},
+				}
+			},
+			want: "# 空\n\n- (空)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Markdown(tt.tree())); got != tt.want {
+				t.Errorf("Markdown() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMermaid(t *testing.T) {
+	tests := []struct {
+		name string
+		tree func() model.TreeResponse
+		want string
+	}{
+		{
+			name: "nested outline with relation labels",
+			tree: sampleTree,
+			want: "flowchart TD\n" +
+				"  n0[\"英語を話せるようになる\"]\n" +
+				"  n1[\"毎日*30分*話す\"]\n" +
+				"  n0 -->|\"how(習慣)\"| n1\n" +
+				"  n2[\"オンライン英会話を予約する\"]\n" +
+				"  n1 -->|\"手段\"| n2\n" +
+				"  n3[\"海外の友人と 話したい\"]\n" +
+				"  n0 -->|\"why\"| n3\n",
+		},
+		{
+			name: "quotes, brackets and neutral edges",
+			tree: func() model.TreeResponse {
+				root, child := uuid.New(), uuid.New()
+				return model.TreeResponse{
+					Nodes: []model.Node{{ID: root, Content: `「"目標"」[A]`}, {ID: child, Content: "<b>#1</b>\n次へ"}},
+					Edges: []model.Edge{
+						{ChildNodeID: root, Relation: model.RelationNeutral},
+						{ParentNodeID: &root, ChildNodeID: child, Relation: model.RelationNeutral},
+					},
+				}
+			},
+			want: "flowchart TD\n" +
+				"  n0[\"「#quot;目標#quot;」[A]\"]\n" +
+				"  n1[\"#lt;b#gt;#35;1#lt;/b#gt; 次へ\"]\n" +
+				"  n0 --> n1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Mermaid(tt.tree())); got != tt.want {
+				t.Errorf("Mermaid() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDOT(t *testing.T) {
+	const header = "  graph [charset=\"UTF-8\"];\n" +
+		"  node [shape=box, style=rounded, fontname=\"Noto Sans CJK JP\"];\n" +
+		"  edge [fontname=\"Noto Sans CJK JP\"];\n"
+
+	tests := []struct {
+		name string
+		tree func() model.TreeResponse
+		want string
+	}{
+		{
+			name: "nested outline with relation labels",
+			tree: sampleTree,
+			want: "digraph \"英語学習\" {\n" + header +
+				"  n0 [label=\"英語を話せるようになる\"];\n" +
+				"  n1 [label=\"毎日*30分*話す\"];\n" +
+				"  n0 -> n1 [label=\"how(習慣)\"];\n" +
+				"  n2 [label=\"オンライン英会話を予約する\"];\n" +
+				"  n1 -> n2 [label=\"手段\"];\n" +
+				"  n3 [label=\"海外の友人と 話したい\"];\n" +
+				"  n0 -> n3 [label=\"why\"];\n" +
+				"}\n",
+		},
+		{
+			name: "quotes, backslashes and neutral edges",
+			tree: func() model.TreeResponse {
+				root, child := uuid.New(), uuid.New()
+				return model.TreeResponse{
+					Project: model.Project{Title: "\"計画\"\n2026"},
+					Nodes:   []model.Node{{ID: root, Content: `C:\目標 "A"`}, {ID: child, Content: ""}},
+					Edges: []model.Edge{
+						{ChildNodeID: root, Relation: model.RelationNeutral},
+						{ParentNodeID: &root, ChildNodeID: child, Relation: model.RelationNeutral},
+					},
+				}
+			},
+			want: "digraph \"\\\"計画\\\" 2026\" {\n" + header +
+				"  n0 [label=\"C:\\\\目標 \\\"A\\\"\"];\n" +
+				"  n1 [label=\"(空)\"];\n" +
+				"  n0 -> n1;\n" +
+				"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(DOT(tt.tree())); got != tt.want {
+				t.Errorf("DOT() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderUnsupportedFormat(t *testing.T) {
+	for _, format := range []string{"", "csv", "Markdown"} {
+		if _, err := Render(format, sampleTree()); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Errorf("Render(%q) error = %v, want ErrUnsupportedFormat", format, err)
+		}
+	}
+}
+
+// outlineLines はアウトラインを「深さ・内容・問い・関係・関係ラベル」の行に平らにして比較しやすくします
+func outlineLines(roots []*OutlineNode) []string {
+	var lines []string
+	var walk func(nodes []*OutlineNode, depth int)
+	walk = func(nodes []*OutlineNode, depth int) {
+		for _, node := range nodes {
+			question, label := "-", "-"
+			if node.Node.Question != nil {
+				question = *node.Node.Question
+			}
+			if node.Edge.RelationLabel != nil {
+				label = *node.Edge.RelationLabel
+			}
+			lines = append(lines, fmt.Sprintf("%d|%s|%s|%s|%s", depth, node.Node.Content, question, node.Edge.Relation, label))
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(roots, 0)
+	return lines
+}
+
+func multiRootTree() model.TreeResponse {
+	first, second := uuid.New(), uuid.New()
+	return model.TreeResponse{
+		Project: model.Project{Title: "二つの目標"},
+		Nodes: []model.Node{
+			{ID: first, Content: "走る"},
+			{ID: second, Content: "読む", Question: strPtr("何を？")},
+		},
+		Edges: []model.Edge{
+			{ChildNodeID: second, Relation: model.RelationNeutral, OrderIndex: 1},
+			{ChildNodeID: first, Relation: model.RelationNeutral, OrderIndex: 0},
+		},
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		format string
+		tree   func() model.TreeResponse
+	}{
+		{format: "opml", tree: sampleTree},
+		{format: "opml", tree: multiRootTree},
+		{format: "freemind", tree: sampleTree},
+		{format: "json", tree: sampleTree},
+		{format: "mm", tree: multiRootTree},
+	}
+
+	for _, tt := range tests {
+		tree := tt.tree()
+		t.Run(tt.format+"/"+tree.Project.Title, func(t *testing.T) {
+			doc, err := Render(tt.format, tree)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			parsed, err := Parse(tt.format, doc.Data)
+			if err != nil {
+				t.Fatalf("Parse() error = %v\n%s", err, doc.Data)
+			}
+
+			if parsed.Title != tree.Project.Title {
+				t.Errorf("Title = %q, want %q", parsed.Title, tree.Project.Title)
+			}
+			if !reflect.DeepEqual(parsed.Description, tree.Project.Description) {
+				t.Errorf("Description = %v, want %v", parsed.Description, tree.Project.Description)
+			}
+			want := outlineLines(BuildOutline(tree.Nodes, tree.Edges))
+			if got := outlineLines(parsed.Roots); !reflect.DeepEqual(got, want) {
+				t.Errorf("outline =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseInvalidDocument(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+	}{
+		{format: "opml", data: "<opml><body><outline text="},
+		{format: "freemind", data: "not xml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if _, err := Parse(tt.format, []byte(tt.data)); !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("Parse() error = %v, want ErrInvalidDocument", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// FreeMind の attribute 要素で質問・関係・プロジェクト情報を保持します
+const (
+	freeMindAttrQuestion           = "question"
+	freeMindAttrRelation           = "relation"
+	freeMindAttrRelationLabel      = "relation_label"
+	freeMindAttrProjectTitle       = "project_title"
+	freeMindAttrProjectDescription = "project_description"
+	// 根ノードが複数ある場合に、それらをまとめるために追加したノードであることを示します
+	freeMindAttrProjectContainer = "project_container"
+)
+
+type freeMindMap struct {
+	XMLName xml.Name     `xml:"map"`
+	Version string       `xml:"version,attr"`
+	Node    freeMindNode `xml:"node"`
+}
+
+type freeMindNode struct {
+	Text       string              `xml:"TEXT,attr"`
+	Attributes []freeMindAttribute `xml:"attribute"`
+	Nodes      []freeMindNode      `xml:"node"`
+}
+
+type freeMindAttribute struct {
+	Name  string `xml:"NAME,attr"`
+	Value string `xml:"VALUE,attr"`
+}
+
+// FreeMind はツリーを FreeMind の .mm 形式で出力します
+func FreeMind(tree model.TreeResponse) ([]byte, error) {
+	roots := BuildOutline(tree.Nodes, tree.Edges)
+
+	var mapRoot freeMindNode
+	if len(roots) == 1 {
+		mapRoot = toFreeMindNode(roots[0])
+	} else {
+		mapRoot = freeMindNode{
+			Text:       tree.Project.Title,
+			Attributes: []freeMindAttribute{{Name: freeMindAttrProjectContainer, Value: "true"}},
+		}
+		for _, root := range roots {
+			mapRoot.Nodes = append(mapRoot.Nodes, toFreeMindNode(root))
+		}
+	}
+	mapRoot.Attributes = append(mapRoot.Attributes, freeMindAttribute{Name: freeMindAttrProjectTitle, Value: tree.Project.Title})
+	if tree.Project.Description != nil {
+		mapRoot.Attributes = append(mapRoot.Attributes, freeMindAttribute{Name: freeMindAttrProjectDescription, Value: *tree.Project.Description})
+	}
+
+	return marshalXML(freeMindMap{Version: "1.0.1", Node: mapRoot})
+}
+
+func toFreeMindNode(node *OutlineNode) freeMindNode {
+	fm := freeMindNode{Text: node.Node.Content}
+	if node.Node.Question != nil {
+		fm.Attributes = append(fm.Attributes, freeMindAttribute{Name: freeMindAttrQuestion, Value: *node.Node.Question})
+	}
+	if node.Edge.Relation != model.RelationNeutral {
+		fm.Attributes = append(fm.Attributes, freeMindAttribute{Name: freeMindAttrRelation, Value: string(node.Edge.Relation)})
+	}
+	if node.Edge.RelationLabel != nil {
+		fm.Attributes = append(fm.Attributes, freeMindAttribute{Name: freeMindAttrRelationLabel, Value: *node.Edge.RelationLabel})
+	}
+	for _, child := range node.Children {
+		fm.Nodes = append(fm.Nodes, toFreeMindNode(child))
+	}
+	return fm
+}
+
+// ParseFreeMind は FreeMind の .mm ドキュメントを読み込みます
+func ParseFreeMind(data []byte) (*Tree, error) {
+	var doc freeMindMap
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	attrs := freeMindAttributes(doc.Node)
+	tree := &Tree{Title: doc.Node.Text}
+	if title, ok := attrs[freeMindAttrProjectTitle]; ok {
+		tree.Title = title
+	}
+	if description, ok := attrs[freeMindAttrProjectDescription]; ok {
+		tree.Description = &description
+	}
+
+	if attrs[freeMindAttrProjectContainer] == "true" {
+		for _, child := range doc.Node.Nodes {
+			tree.Roots = append(tree.Roots, fromFreeMindNode(child))
+		}
+	} else {
+		tree.Roots = []*OutlineNode{fromFreeMindNode(doc.Node)}
+	}
+	return tree, nil
+}
+
+func fromFreeMindNode(fm freeMindNode) *OutlineNode {
+	attrs := freeMindAttributes(fm)
+	node := &OutlineNode{
+		Node: model.Node{Content: fm.Text},
+		Edge: model.Edge{Relation: relationOrNeutral(attrs[freeMindAttrRelation])},
+	}
+	if question, ok := attrs[freeMindAttrQuestion]; ok {
+		node.Node.Question = &question
+	}
+	if label, ok := attrs[freeMindAttrRelationLabel]; ok {
+		node.Edge.RelationLabel = &label
+	}
+	for _, child := range fm.Nodes {
+		node.Children = append(node.Children, fromFreeMindNode(child))
+	}
+	return node
+}
+
+func freeMindAttributes(fm freeMindNode) map[string]string {
+	attrs := make(map[string]string, len(fm.Attributes))
+	for _, attr := range fm.Attributes {
+		attrs[attr.Name] = attr.Value
+	}
+	return attrs
+}
@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// icalLineLimit は RFC 5545 で折り返しが必要になる1行のオクテット数です
+const icalLineLimit = 75
+
+// ICalendar は期日のあるノードを終日の予定として iCalendar (RFC 5545) 形式で出力します
+func ICalendar(items []model.AgendaItem, now time.Time) []byte {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//mokuhyo//agenda//JA")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+escapeICal("目標の期日"))
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, item := range items {
+		if item.Node.DueDate == nil {
+			continue
+		}
+		due := item.Node.DueDate.Time
+		path := append(append([]string{}, item.ProjectTitle), item.Path...)
+
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, fmt.Sprintf("UID:%s@mokuhyo", item.Node.ID))
+		writeICalLine(&buf, "DTSTAMP:"+stamp)
+		writeICalLine(&buf, "LAST-MODIFIED:"+item.Node.UpdatedAt.UTC().Format("20060102T150405Z"))
+		writeICalLine(&buf, "DTSTART;VALUE=DATE:"+due.Format("20060102"))
+		writeICalLine(&buf, "DTEND;VALUE=DATE:"+due.AddDate(0, 0, 1).Format("20060102"))
+		writeICalLine(&buf, "SUMMARY:"+escapeICal(item.Node.Content))
+		writeICalLine(&buf, "DESCRIPTION:"+escapeICal(strings.Join(path, " > ")))
+		writeICalLine(&buf, "STATUS:CONFIRMED")
+		writeICalLine(&buf, "END:VEVENT")
+	}
+
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeICal(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+// writeICalLine は1行を CRLF で書き出し、75オクテットを超える場合は文字の途中で切らずに折り返します
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行は先頭の空白1オクテットを含めて75オクテットに収める
+		limit = icalLineLimit - 1
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
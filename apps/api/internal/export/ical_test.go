@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestEscapeICal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain", want: "plain"},
+		{in: `a\b`, want: `a\\b`},
+		{in: "a;b,c", want: `a\;b\,c`},
+		{in: "line1\nline2", want: `line1\nline2`},
+		{in: "line1\r\nline2\rline3", want: `line1\nline2\nline3`},
+		{in: "目標 > 手段", want: "目標 > 手段"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeICal(tt.in); got != tt.want {
+			t.Errorf("escapeICal(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteICalLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLines int
+	}{
+		{name: "short", line: "SUMMARY:short", wantLines: 1},
+		{name: "exactly 75 octets", line: strings.Repeat("a", 75), wantLines: 1},
+		{name: "76 octets", line: strings.Repeat("a", 76), wantLines: 2},
+		{name: "ascii continuation", line: strings.Repeat("a", 75+74+1), wantLines: 3},
+		{name: "multibyte", line: "SUMMARY:" + strings.Repeat("目標", 40), wantLines: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeICalLine(&buf, tt.line)
+			out := buf.String()
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q does not end with CRLF", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d physical lines, want %d", len(lines), tt.wantLines)
+			}
+			var unfolded strings.Builder
+			for i, line := range lines {
+				if len(line) > icalLineLimit {
+					t.Errorf("line %d has %d octets, want at most %d", i, len(line), icalLineLimit)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a multibyte character: %q", i, line)
+				}
+				if i > 0 {
+					if !strings.HasPrefix(line, " ") {
+						t.Errorf("continuation line %d does not start with a space: %q", i, line)
+					}
+					line = line[1:]
+				}
+				unfolded.WriteString(line)
+			}
+			if unfolded.String() != tt.line {
+				t.Errorf("unfolded = %q, want %q", unfolded.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestICalendar(t *testing.T) {
+	due := model.NewDate(time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC))
+	withDue := model.Node{ID: uuid.New(), Content: "発表, 準備", DueDate: &due, UpdatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)}
+	withoutDue := model.Node{ID: uuid.New(), Content: "期日なし"}
+	items := []model.AgendaItem{
+		{ProjectTitle: "仕事", Node: withDue, Path: []string{"昇進する"}},
+		{ProjectTitle: "仕事", Node: withoutDue},
+	}
+
+	out := string(ICalendar(items, time.Date(2026, 10, 17, 12, 0, 0, 0, time.FixedZone("JST", 9*60*60))))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:" + withDue.ID.String() + "@mokuhyo\r\n",
+		"DTSTAMP:20261017T030000Z\r\n",
+		"LAST-MODIFIED:20261001T090000Z\r\n",
+		"DTSTART;VALUE=DATE:20261231\r\n",
+		"DTEND;VALUE=DATE:20270101\r\n",
+		"SUMMARY:発表\\, 準備\r\n",
+		"DESCRIPTION:仕事 > 昇進する\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("got %d events, want 1 (items without due date are skipped)", n)
+	}
+	if strings.Contains(out, withoutDue.ID.String()) {
+		t.Errorf("output contains the node without due date")
+	}
+}
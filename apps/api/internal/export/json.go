@@ -0,0 +1,191 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+const (
+	// JSONFormatName は JSON ドキュメントの種別を示す識別子です
+	JSONFormatName = "mokuhyo-project"
+	// JSONSchemaVersion は現在出力している JSON ドキュメントのスキーマバージョンです
+	// フィールドの意味を変える変更を加えた場合はこの値を上げ、ParseJSON で旧バージョンを読み替えます
+	JSONSchemaVersion = 1
+)
+
+// JSONDocument はプロジェクトのバックアップ・移行用の JSON ドキュメントです
+type JSONDocument struct {
+	Format        string      `json:"format"`
+	SchemaVersion int         `json:"schema_version"`
+	ExportedAt    time.Time   `json:"exported_at"`
+	Project       JSONProject `json:"project"`
+	Nodes         []JSONNode  `json:"nodes"`
+	Edges         []JSONEdge  `json:"edges"`
+}
+
+type JSONProject struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+}
+
+type JSONNode struct {
+	ID       uuid.UUID        `json:"id"`
+	Content  string           `json:"content"`
+	Question *string          `json:"question,omitempty"`
+	Status   model.NodeStatus `json:"status,omitempty"`
+	DueDate  *model.Date      `json:"due_date,omitempty"`
+	Weight   *float64         `json:"weight,omitempty"`
+}
+
+type JSONEdge struct {
+	ParentNodeID  *uuid.UUID         `json:"parent_node_id"`
+	ChildNodeID   uuid.UUID          `json:"child_node_id"`
+	Relation      model.RelationType `json:"relation"`
+	RelationLabel *string            `json:"relation_label,omitempty"`
+	OrderIndex    int                `json:"order_index"`
+}
+
+// JSON はツリーをスキーマバージョン付きの JSON ドキュメントとして出力します
+func JSON(tree model.TreeResponse) ([]byte, error) {
+	doc := JSONDocument{
+		Format:        JSONFormatName,
+		SchemaVersion: JSONSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Project: JSONProject{
+			Title:       tree.Project.Title,
+			Description: tree.Project.Description,
+		},
+		Nodes: make([]JSONNode, 0, len(tree.Nodes)),
+		Edges: make([]JSONEdge, 0, len(tree.Edges)),
+	}
+	for _, node := range tree.Nodes {
+		doc.Nodes = append(doc.Nodes, JSONNode{
+			ID:       node.ID,
+			Content:  node.Content,
+			Question: node.Question,
+			Status:   node.Status,
+			DueDate:  node.DueDate,
+			Weight:   node.Weight,
+		})
+	}
+	for _, edge := range tree.Edges {
+		doc.Edges = append(doc.Edges, JSONEdge{
+			ParentNodeID:  edge.ParentNodeID,
+			ChildNodeID:   edge.ChildNodeID,
+			Relation:      edge.Relation,
+			RelationLabel: edge.RelationLabel,
+			OrderIndex:    edge.OrderIndex,
+		})
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode json: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// ParseJSON は JSON ドキュメントを読み込み、エッジが単一の根を持つツリーになっていることを検証します
+func ParseJSON(data []byte) (*Tree, error) {
+	var doc JSONDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+	if doc.Format != JSONFormatName {
+		return nil, fmt.Errorf("%w: format must be %q", ErrInvalidDocument, JSONFormatName)
+	}
+	if doc.SchemaVersion < 1 || doc.SchemaVersion > JSONSchemaVersion {
+		return nil, fmt.Errorf("%w: unsupported schema_version %d", ErrInvalidDocument, doc.SchemaVersion)
+	}
+	if doc.Project.Title == "" {
+		return nil, fmt.Errorf("%w: project title is required", ErrInvalidDocument)
+	}
+
+	outlineByID := make(map[uuid.UUID]*OutlineNode, len(doc.Nodes))
+	for _, node := range doc.Nodes {
+		if _, dup := outlineByID[node.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate node id %s", ErrInvalidDocument, node.ID)
+		}
+		switch node.Status {
+		case "", model.NodeStatusTodo, model.NodeStatusDoing, model.NodeStatusDone, model.NodeStatusDropped:
+		default:
+			return nil, fmt.Errorf("%w: node %s has unknown status %q", ErrInvalidDocument, node.ID, node.Status)
+		}
+		if node.Weight != nil && *node.Weight <= 0 {
+			return nil, fmt.Errorf("%w: node %s has non-positive weight", ErrInvalidDocument, node.ID)
+		}
+		outlineByID[node.ID] = &OutlineNode{Node: model.Node{
+			Content:  node.Content,
+			Question: node.Question,
+			Status:   node.Status,
+			DueDate:  node.DueDate,
+			Weight:   node.Weight,
+		}}
+	}
+
+	// edges_unique_child と同様に、各ノードはちょうど1本のエッジの子である必要がある
+	var root *OutlineNode
+	hasEdge := make(map[uuid.UUID]bool, len(doc.Edges))
+	orderIndex := make(map[*OutlineNode]int, len(doc.Edges))
+	for _, edge := range doc.Edges {
+		child, ok := outlineByID[edge.ChildNodeID]
+		if !ok {
+			return nil, fmt.Errorf("%w: edge references unknown node %s", ErrInvalidDocument, edge.ChildNodeID)
+		}
+		if hasEdge[edge.ChildNodeID] {
+			return nil, fmt.Errorf("%w: node %s has more than one parent edge", ErrInvalidDocument, edge.ChildNodeID)
+		}
+		hasEdge[edge.ChildNodeID] = true
+		child.Edge = model.Edge{Relation: relationOrNeutral(string(edge.Relation)), RelationLabel: edge.RelationLabel}
+		orderIndex[child] = edge.OrderIndex
+
+		if edge.ParentNodeID == nil {
+			if root != nil {
+				return nil, fmt.Errorf("%w: tree must have exactly one root", ErrInvalidDocument)
+			}
+			root = child
+			continue
+		}
+		parent, ok := outlineByID[*edge.ParentNodeID]
+		if !ok {
+			return nil, fmt.Errorf("%w: edge references unknown parent node %s", ErrInvalidDocument, *edge.ParentNodeID)
+		}
+		parent.Children = append(parent.Children, child)
+	}
+	if root == nil {
+		return nil, fmt.Errorf("%w: tree must have exactly one root", ErrInvalidDocument)
+	}
+	for id := range outlineByID {
+		if !hasEdge[id] {
+			return nil, fmt.Errorf("%w: node %s has no edge", ErrInvalidDocument, id)
+		}
+	}
+
+	// 根から到達できないノードがあれば、それは循環の一部
+	reached := 0
+	var walk func(node *OutlineNode)
+	walk = func(node *OutlineNode) {
+		reached++
+		sort.SliceStable(node.Children, func(i, j int) bool {
+			return orderIndex[node.Children[i]] < orderIndex[node.Children[j]]
+		})
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(root)
+	if reached != len(outlineByID) {
+		return nil, fmt.Errorf("%w: edges contain a cycle", ErrInvalidDocument)
+	}
+
+	return &Tree{
+		Title:       doc.Project.Title,
+		Description: doc.Project.Description,
+		Roots:       []*OutlineNode{root},
+	}, nil
+}
@@ -0,0 +1,106 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestJSONKeepsProgressFields(t *testing.T) {
+	tree := sampleTree()
+	due := model.NewDate(time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC))
+	weight := 2.5
+	tree.Nodes[1].DueDate = &due
+	tree.Nodes[1].Weight = &weight
+
+	data, err := JSON(tree)
+	if err != nil {
+		t.Fatalf("JSON() error = %v", err)
+	}
+	parsed, err := ParseJSON(data)
+	if err != nil {
+		t.Fatalf("ParseJSON() error = %v", err)
+	}
+
+	// sampleTree の order_index では tree.Nodes[1] が根の最初の子になる
+	got := parsed.Roots[0].Children[0].Node
+	if got.Status != model.NodeStatusDoing {
+		t.Errorf("Status = %q, want %q", got.Status, model.NodeStatusDoing)
+	}
+	if got.DueDate == nil || !got.DueDate.Equal(due.Time) {
+		t.Errorf("DueDate = %v, want %v", got.DueDate, due)
+	}
+	if got.Weight == nil || *got.Weight != weight {
+		t.Errorf("Weight = %v, want %v", got.Weight, weight)
+	}
+}
+
+func TestParseJSONInvalid(t *testing.T) {
+	root, child := uuid.New(), uuid.New()
+	valid := func() JSONDocument {
+		return JSONDocument{
+			Format:        JSONFormatName,
+			SchemaVersion: JSONSchemaVersion,
+			Project:       JSONProject{Title: "目標"},
+			Nodes:         []JSONNode{{ID: root, Content: "根"}, {ID: child, Content: "子"}},
+			Edges: []JSONEdge{
+				{ChildNodeID: root, Relation: model.RelationNeutral},
+				{ParentNodeID: &root, ChildNodeID: child, Relation: model.RelationWhy},
+			},
+		}
+	}
+	zero := 0.0
+
+	tests := []struct {
+		name   string
+		modify func(doc *JSONDocument)
+	}{
+		{name: "wrong format", modify: func(doc *JSONDocument) { doc.Format = "other" }},
+		{name: "future schema version", modify: func(doc *JSONDocument) { doc.SchemaVersion = JSONSchemaVersion + 1 }},
+		{name: "missing schema version", modify: func(doc *JSONDocument) { doc.SchemaVersion = 0 }},
+		{name: "blank title", modify: func(doc *JSONDocument) { doc.Project.Title = "" }},
+		{name: "duplicate node id", modify: func(doc *JSONDocument) { doc.Nodes[1].ID = root }},
+		{name: "unknown status", modify: func(doc *JSONDocument) { doc.Nodes[0].Status = "blocked" }},
+		{name: "non-positive weight", modify: func(doc *JSONDocument) { doc.Nodes[0].Weight = &zero }},
+		{name: "edge to unknown node", modify: func(doc *JSONDocument) { doc.Edges[1].ChildNodeID = uuid.New() }},
+		{name: "edge from unknown parent", modify: func(doc *JSONDocument) { unknown := uuid.New(); doc.Edges[1].ParentNodeID = &unknown }},
+		{name: "node with two parents", modify: func(doc *JSONDocument) { doc.Edges = append(doc.Edges, doc.Edges[1]) }},
+		{name: "node without edge", modify: func(doc *JSONDocument) { doc.Edges = doc.Edges[:1] }},
+		{name: "two roots", modify: func(doc *JSONDocument) { doc.Edges[1].ParentNodeID = nil }},
+		{name: "no root", modify: func(doc *JSONDocument) { doc.Edges[0].ParentNodeID = &child }},
+		{name: "cycle", modify: func(doc *JSONDocument) {
+			other := uuid.New()
+			doc.Nodes = append(doc.Nodes, JSONNode{ID: other, Content: "循環"})
+			doc.Edges[1].ParentNodeID = &other
+			doc.Edges = append(doc.Edges, JSONEdge{ParentNodeID: &child, ChildNodeID: other})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := valid()
+			tt.modify(&doc)
+			data, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if _, err := ParseJSON(data); !errors.Is(err, ErrInvalidDocument) {
+				t.Errorf("ParseJSON() error = %v, want ErrInvalidDocument", err)
+			}
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		data, err := json.Marshal(valid())
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if _, err := ParseJSON(data); err != nil {
+			t.Errorf("ParseJSON() error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// Markdown はツリーを入れ子の箇条書きアウトラインとして出力します
+// 各ノードの質問と関係（relation / relation_label）は括弧書きの注釈として付与します
+func Markdown(tree model.TreeResponse) []byte {
+	var builder strings.Builder
+	builder.WriteString("# " + escapeMarkdown(singleLine(tree.Project.Title)) + "\n")
+	if tree.Project.Description != nil && strings.TrimSpace(*tree.Project.Description) != "" {
+		builder.WriteString("\n> " + escapeMarkdown(singleLine(*tree.Project.Description)) + "\n")
+	}
+	builder.WriteString("\n")
+
+	for _, root := range BuildOutline(tree.Nodes, tree.Edges) {
+		writeMarkdownNode(&builder, root, 0)
+	}
+	return []byte(builder.String())
+}
+
+func writeMarkdownNode(builder *strings.Builder, node *OutlineNode, depth int) {
+	builder.WriteString(strings.Repeat("  ", depth))
+	builder.WriteString("- " + escapeMarkdown(nodeText(node.Node)))
+	if annotation := markdownAnnotation(node); annotation != "" {
+		builder.WriteString(" （" + annotation + "）")
+	}
+	builder.WriteString("\n")
+
+	for _, child := range node.Children {
+		writeMarkdownNode(builder, child, depth+1)
+	}
+}
+
+func markdownAnnotation(node *OutlineNode) string {
+	var parts []string
+	if node.Node.Question != nil && strings.TrimSpace(*node.Node.Question) != "" {
+		parts = append(parts, "問い: "+escapeMarkdown(singleLine(*node.Node.Question)))
+	}
+	if label := relationText(node.Edge); label != "" {
+		parts = append(parts, "関係: "+escapeMarkdown(label))
+	}
+	return strings.Join(parts, " / ")
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+func escapeMarkdown(text string) string {
+	return markdownEscaper.Replace(text)
+}
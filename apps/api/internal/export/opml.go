@@ -0,0 +1,111 @@
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// OPML 2.0 の outline 要素に、質問と関係を独自属性として保持します
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    opmlHead `xml:"head"`
+	Body    opmlBody `xml:"body"`
+}
+
+type opmlHead struct {
+	Title       string  `xml:"title"`
+	Description *string `xml:"description,omitempty"`
+}
+
+type opmlBody struct {
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+type opmlOutline struct {
+	Text          string        `xml:"text,attr"`
+	Question      *string       `xml:"question,attr,omitempty"`
+	Relation      string        `xml:"relation,attr,omitempty"`
+	RelationLabel *string       `xml:"relationLabel,attr,omitempty"`
+	Outlines      []opmlOutline `xml:"outline"`
+}
+
+// OPML はツリーを OPML 2.0 形式で出力します
+func OPML(tree model.TreeResponse) ([]byte, error) {
+	doc := opmlDocument{
+		Version: "2.0",
+		Head: opmlHead{
+			Title:       tree.Project.Title,
+			Description: tree.Project.Description,
+		},
+	}
+	for _, root := range BuildOutline(tree.Nodes, tree.Edges) {
+		doc.Body.Outlines = append(doc.Body.Outlines, toOPMLOutline(root))
+	}
+	return marshalXML(doc)
+}
+
+func toOPMLOutline(node *OutlineNode) opmlOutline {
+	outline := opmlOutline{
+		Text:          node.Node.Content,
+		Question:      node.Node.Question,
+		RelationLabel: node.Edge.RelationLabel,
+	}
+	if node.Edge.Relation != model.RelationNeutral {
+		outline.Relation = string(node.Edge.Relation)
+	}
+	for _, child := range node.Children {
+		outline.Outlines = append(outline.Outlines, toOPMLOutline(child))
+	}
+	return outline
+}
+
+// ParseOPML は OPML ドキュメントを読み込みます
+func ParseOPML(data []byte) (*Tree, error) {
+	var doc opmlDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+	}
+
+	tree := &Tree{
+		Title:       doc.Head.Title,
+		Description: doc.Head.Description,
+	}
+	for _, outline := range doc.Body.Outlines {
+		tree.Roots = append(tree.Roots, fromOPMLOutline(outline))
+	}
+	return tree, nil
+}
+
+func fromOPMLOutline(outline opmlOutline) *OutlineNode {
+	node := &OutlineNode{
+		Node: model.Node{Content: outline.Text, Question: outline.Question},
+		Edge: model.Edge{Relation: relationOrNeutral(outline.Relation), RelationLabel: outline.RelationLabel},
+	}
+	for _, child := range outline.Outlines {
+		node.Children = append(node.Children, fromOPMLOutline(child))
+	}
+	return node
+}
+
+func relationOrNeutral(relation string) model.RelationType {
+	if relation == "" {
+		return model.RelationNeutral
+	}
+	return model.RelationType(relation)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to encode xml: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
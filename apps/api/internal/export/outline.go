@@ -0,0 +1,87 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// OutlineNode はエッジをたどって組み立てたツリー上の1ノードです
+type OutlineNode struct {
+	Node     model.Node
+	Edge     model.Edge
+	Children []*OutlineNode
+}
+
+// BuildOutline はフラットなノードとエッジから、order_index 順に並んだ根ノードの一覧を組み立てます
+func BuildOutline(nodes []model.Node, edges []model.Edge) []*OutlineNode {
+	outlineByID := make(map[uuid.UUID]*OutlineNode, len(nodes))
+	for _, node := range nodes {
+		outlineByID[node.ID] = &OutlineNode{Node: node}
+	}
+
+	var roots []*OutlineNode
+	for _, edge := range edges {
+		child, ok := outlineByID[edge.ChildNodeID]
+		if !ok {
+			continue
+		}
+		child.Edge = edge
+		if edge.ParentNodeID == nil {
+			roots = append(roots, child)
+			continue
+		}
+		parent, ok := outlineByID[*edge.ParentNodeID]
+		if !ok {
+			continue
+		}
+		parent.Children = append(parent.Children, child)
+	}
+
+	sortOutline(roots)
+	return roots
+}
+
+func sortOutline(nodes []*OutlineNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return nodes[i].Edge.OrderIndex < nodes[j].Edge.OrderIndex
+	})
+	for _, node := range nodes {
+		sortOutline(node.Children)
+	}
+}
+
+// relationText は関係の表示文字列を返します（neutral の場合は空文字列）
+func relationText(edge model.Edge) string {
+	label := ""
+	if edge.RelationLabel != nil {
+		label = singleLine(*edge.RelationLabel)
+	}
+	switch {
+	case edge.Relation == model.RelationCustom && label != "":
+		return label
+	case edge.Relation == "" || edge.Relation == model.RelationNeutral:
+		return label
+	case label != "":
+		return fmt.Sprintf("%s(%s)", edge.Relation, label)
+	default:
+		return string(edge.Relation)
+	}
+}
+
+func nodeText(node model.Node) string {
+	content := singleLine(node.Content)
+	if content == "" {
+		return "(空)"
+	}
+	return content
+}
+
+var lineBreakReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ")
+
+func singleLine(text string) string {
+	return strings.TrimSpace(lineBreakReplacer.Replace(text))
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+// defaultAgendaDays は days を省略したときに含める、今日から先の日数です
+const defaultAgendaDays = 7
+
+type AgendaHandler struct {
+	agendaService *service.AgendaService
+	auditService  *service.AuditService
+}
+
+func NewAgendaHandler(agendaService *service.AgendaService, auditService *service.AuditService) *AgendaHandler {
+	return &AgendaHandler{agendaService: agendaService, auditService: auditService}
+}
+
+// GetAgenda は期限切れと days 日以内に期日を迎えるノードを返します（tz で「今日」を決めるタイムゾーンを指定、省略時はUTC）
+func (h *AgendaHandler) GetAgenda(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	days := defaultAgendaDays
+	if raw := c.Query("days"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid days"})
+			return
+		}
+		days = n
+	}
+
+	loc := time.UTC
+	if raw := c.Query("tz"); raw != "" {
+		l, err := time.LoadLocation(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tz"})
+			return
+		}
+		loc = l
+	}
+
+	agenda, err := h.agendaService.GetAgenda(c.Request.Context(), userID, days, loc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, agenda)
+}
+
+func (h *AgendaHandler) CreateCalendarFeed(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	feed, err := h.agendaService.CreateCalendarFeed(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditCalendarFeedCreate})
+
+	c.JSON(http.StatusCreated, feed)
+}
+
+func (h *AgendaHandler) RevokeCalendarFeed(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	if err := h.agendaService.RevokeCalendarFeed(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrCalendarFeedNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditCalendarFeedRevoke})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// GetCalendarFeed は認証なしでフィードのトークンから iCalendar を返します
+func (h *AgendaHandler) GetCalendarFeed(c *gin.Context) {
+	data, err := h.agendaService.GetCalendarFeed(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrCalendarFeedNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", data)
+}
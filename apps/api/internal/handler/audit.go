@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditEvents はリクエストしたユーザー自身の監査ログを新しい順に返します
+func (h *AuditHandler) ListAuditEvents(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	limit := defaultAuditLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = n
+	}
+
+	var before *int64
+	if raw := c.Query("before"); raw != "" {
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid before"})
+			return
+		}
+		before = &n
+	}
+
+	result, err := h.auditService.ListEvents(c.Request.Context(), userID, before, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// recordAudit は成功した操作を、リクエスト元の IP アドレスと User-Agent とともに監査ログに記録します
+// 操作自体は完了しているため、記録に失敗してもレスポンスは変えずにログに残します
+func recordAudit(c *gin.Context, auditService *service.AuditService, event model.AuditEvent) {
+	event.IPAddress = c.ClientIP()
+	event.UserAgent = c.Request.UserAgent()
+	if err := auditService.Record(c.Request.Context(), event); err != nil {
+		log.Printf("Failed to record audit event %s for user %s: %v", event.Action, event.UserID, err)
+	}
+}
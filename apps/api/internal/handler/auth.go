@@ -4,19 +4,22 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mokuhyo-driven-test/api/internal/model"
 	"github.com/mokuhyo-driven-test/api/internal/service"
 	"github.com/mokuhyo-driven-test/api/pkg/auth"
 )
 
 type AuthHandler struct {
-	authService *service.AuthService
-	oauthConfig *auth.GoogleOAuthConfig
+	authService  *service.AuthService
+	auditService *service.AuditService
+	oauthConfig  *auth.GoogleOAuthConfig
 }
 
-func NewAuthHandler(authService *service.AuthService, oauthConfig *auth.GoogleOAuthConfig) *AuthHandler {
+func NewAuthHandler(authService *service.AuthService, auditService *service.AuditService, oauthConfig *auth.GoogleOAuthConfig) *AuthHandler {
 	return &AuthHandler{
-		authService: authService,
-		oauthConfig: oauthConfig,
+		authService:  authService,
+		auditService: auditService,
+		oauthConfig:  oauthConfig,
 	}
 }
 
@@ -88,6 +91,7 @@ func (h *AuthHandler) HandleGoogleAuth(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get or create user", "details": err.Error()})
 		return
 	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: user.ID, Action: model.AuditLogin})
 
 	// user.Pictureをstringに変換（nilの場合は空文字列）
 	pictureStr := ""
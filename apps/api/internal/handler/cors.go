@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS は allowedOrigins に含まれるオリジンからのリクエストにだけCORSのヘッダーを付けます
+// allowedOrigins に "*" を含めると、従来どおり Access-Control-Allow-Origin: * ですべてのオリジンを許可します
+func CORS(allowedOrigins []string) gin.HandlerFunc {
+	allowAll := slices.Contains(allowedOrigins, "*")
+	return func(c *gin.Context) {
+		allowOrigin := "*"
+		if !allowAll {
+			c.Writer.Header().Add("Vary", "Origin")
+			allowOrigin = ""
+			if origin := c.GetHeader("Origin"); origin != "" && originAllowed(allowedOrigins, origin) {
+				allowOrigin = origin
+			}
+		}
+		if allowOrigin != "" {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-Match, If-None-Match")
+			c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
+			c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		}
+
+		if c.Request.Method == "OPTIONS" {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func originAllowed(allowedOrigins []string, origin string) bool {
+	return slices.Contains(allowedOrigins, "*") || slices.Contains(allowedOrigins, origin)
+}
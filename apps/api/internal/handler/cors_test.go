@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		allowedOrigins []string
+		origin         string
+		wantOrigin     string
+	}{
+		{name: "allow all without origin", allowedOrigins: []string{"*"}, wantOrigin: "*"},
+		{name: "allow all with origin", allowedOrigins: []string{"*"}, origin: "https://other.example.com", wantOrigin: "*"},
+		{name: "listed origin", allowedOrigins: []string{"https://app.example.com"}, origin: "https://app.example.com", wantOrigin: "https://app.example.com"},
+		{name: "unlisted origin", allowedOrigins: []string{"https://app.example.com"}, origin: "https://evil.example.com", wantOrigin: ""},
+		{name: "no origin", allowedOrigins: []string{"https://app.example.com"}, wantOrigin: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(CORS(tt.allowedOrigins))
+			r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			for _, method := range []string{http.MethodGet, http.MethodOptions} {
+				req := httptest.NewRequest(method, "/", nil)
+				if tt.origin != "" {
+					req.Header.Set("Origin", tt.origin)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+					t.Errorf("%s: Access-Control-Allow-Origin = %q, want %q", method, got, tt.wantOrigin)
+				}
+				wantStatus := http.StatusOK
+				if method == http.MethodOptions {
+					wantStatus = http.StatusNoContent
+				}
+				if w.Code != wantStatus {
+					t.Errorf("%s: status = %d, want %d", method, w.Code, wantStatus)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -41,29 +42,45 @@ func (h *EdgeHandler) UpdateEdge(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
+	expectedRevision, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var req model.UpdateEdgeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.edgeService.UpdateEdge(c.Request.Context(), edgeID, req); err != nil {
+	revision, err := h.edgeService.UpdateEdge(c.Request.Context(), userID, projectID, edgeID, req, expectedRevision)
+	if err != nil {
+		if errors.Is(err, service.ErrEdgeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+	setETag(c, revision)
+	c.JSON(http.StatusOK, gin.H{"ok": true, "revision": revision})
 }
 
 func (h *EdgeHandler) Reorder(c *gin.Context) {
@@ -79,27 +96,39 @@ func (h *EdgeHandler) Reorder(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
+	expectedRevision, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var req model.ReorderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.edgeService.Reorder(c.Request.Context(), projectID, req); err != nil {
+	revision, err := h.edgeService.Reorder(c.Request.Context(), userID, projectID, req, expectedRevision)
+	if err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+	setETag(c, revision)
+	c.JSON(http.StatusOK, gin.H{"ok": true, "revision": revision})
 }
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// setETag はノードのバージョンまたはプロジェクトのリビジョンを ETag ヘッダーに設定します
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", formatETag(version))
+}
+
+func formatETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch は If-Match ヘッダーの値をバージョンとして返します
+// ヘッダーがない場合と "*" の場合は nil（照合しない）を返します
+func parseIfMatch(c *gin.Context) (*int64, error) {
+	return parseETag(c.GetHeader("If-Match"))
+}
+
+func parseETag(header string) (*int64, error) {
+	value := strings.TrimSpace(header)
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	value = strings.Trim(value, `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, errInvalidIfMatch
+	}
+	return &version, nil
+}
+
+// matchesIfNoneMatch は If-None-Match ヘッダーが現在の ETag と一致するかを返します
+func matchesIfNoneMatch(c *gin.Context, version int64) bool {
+	for _, value := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" {
+			return true
+		}
+		if parsed, err := parseETag(value); err == nil && parsed != nil && *parsed == version {
+			return true
+		}
+	}
+	return false
+}
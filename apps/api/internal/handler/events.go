@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+const (
+	// eventsHeartbeatInterval はプロキシに接続を切られないよう送るコメント行・ping の間隔です
+	eventsHeartbeatInterval = 25 * time.Second
+	// websocketPongWait はクライアントからの応答がない接続を切るまでの時間です
+	websocketPongWait  = 2 * eventsHeartbeatInterval
+	websocketWriteWait = 10 * time.Second
+
+	// websocketBearerProtocol は Sec-WebSocket-Protocol でアクセストークンを渡すときの目印です
+	// クライアントは ["bearer", <トークン>] を指定し、サーバーは "bearer" だけを選んで返します
+	websocketBearerProtocol = "bearer"
+)
+
+type EventsHandler struct {
+	nodeService    *service.NodeService
+	projectService *service.ProjectService
+	upgrader       websocket.Upgrader
+}
+
+// NewEventsHandler は allowedOrigins（CORSと同じ許可リスト）からの WebSocket 接続だけを受け付けるハンドラーを作成します
+func NewEventsHandler(nodeService *service.NodeService, projectService *service.ProjectService, allowedOrigins []string) *EventsHandler {
+	return &EventsHandler{
+		nodeService:    nodeService,
+		projectService: projectService,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 4096,
+			Subprotocols:    []string{websocketBearerProtocol},
+			CheckOrigin: func(r *http.Request) bool {
+				// ブラウザ以外のクライアントは Origin を送らない
+				origin := r.Header.Get("Origin")
+				return origin == "" || originAllowed(allowedOrigins, origin)
+			},
+		},
+	}
+}
+
+// StreamProjectEvents はプロジェクトのイベントを Server-Sent Events で配信します
+func (h *EventsHandler) StreamProjectEvents(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	subscription, unsubscribe, pendingNodeIDs, err := h.nodeService.SubscribeEvents(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	// 購読前に作成されたノードの生成状況を最初に伝える
+	c.SSEvent(events.TypeQuestionPending, events.Event{
+		Type:      events.TypeQuestionPending,
+		ProjectID: projectID,
+		Data:      model.QuestionPendingEvent{NodeIDs: pendingNodeIDs},
+	})
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-subscription:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
+
+// ProjectSocket はプロジェクトのイベントを WebSocket で配信します
+// ブラウザの WebSocket はヘッダーを付けられないため、アクセストークンは Sec-WebSocket-Protocol でも受け付けます
+func (h *EventsHandler) ProjectSocket(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	// 購読に失敗した場合は接続を切り替える前にエラーを返す
+	subscription, unsubscribe, pendingNodeIDs, err := h.nodeService.SubscribeEvents(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer unsubscribe()
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrade がエラーレスポンスを書き込み済み
+		return
+	}
+	defer conn.Close()
+
+	// クライアントからのメッセージは読み捨て、切断だけを検知する
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadLimit(512)
+		conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(websocketPongWait))
+		})
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(event events.Event) error {
+		conn.SetWriteDeadline(time.Now().Add(websocketWriteWait))
+		return conn.WriteJSON(event)
+	}
+
+	// 購読前に作成されたノードの生成状況を最初に伝える
+	if err := write(events.Event{
+		Type:      events.TypeQuestionPending,
+		ProjectID: projectID,
+		Data:      model.QuestionPendingEvent{NodeIDs: pendingNodeIDs},
+	}); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-subscription:
+			if !ok {
+				return
+			}
+			if err := write(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(websocketWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// WebSocketTokenFromProtocol は WebSocket のハンドシェイクに限り、Sec-WebSocket-Protocol の
+// ["bearer", <トークン>] を Authorization ヘッダーとして扱います
+// URL に含めるとアクセスログに残るため、クエリではトークンを受け付けません。認証ミドルウェアより前に登録してください
+func WebSocketTokenFromProtocol(c *gin.Context) {
+	if c.GetHeader("Authorization") == "" && websocket.IsWebSocketUpgrade(c.Request) {
+		protocols := websocket.Subprotocols(c.Request)
+		if len(protocols) >= 2 && protocols[0] == websocketBearerProtocol && protocols[1] != "" {
+			c.Request.Header.Set("Authorization", "Bearer "+protocols[1])
+		}
+	}
+	c.Next()
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+type HistoryHandler struct {
+	historyService *service.HistoryService
+	projectService *service.ProjectService
+}
+
+func NewHistoryHandler(historyService *service.HistoryService, projectService *service.ProjectService) *HistoryHandler {
+	return &HistoryHandler{
+		historyService: historyService,
+		projectService: projectService,
+	}
+}
+
+// Undo はリクエストしたユーザーが最後に行った操作を取り消します
+func (h *HistoryHandler) Undo(c *gin.Context) {
+	h.step(c, h.historyService.Undo)
+}
+
+// Redo はリクエストしたユーザーが最後に取り消した操作をやり直します
+func (h *HistoryHandler) Redo(c *gin.Context) {
+	h.step(c, h.historyService.Redo)
+}
+
+func (h *HistoryHandler) step(c *gin.Context, apply func(ctx context.Context, userID, projectID uuid.UUID) (*model.HistoryResponse, error)) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	resp, err := apply(c.Request.Context(), userID, projectID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNothingToUndo), errors.Is(err, service.ErrNothingToRedo), errors.Is(err, service.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrUndoConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	setETag(c, resp.Revision)
+	c.JSON(http.StatusOK, resp)
+}
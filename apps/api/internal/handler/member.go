@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+type MemberHandler struct {
+	memberService  *service.MemberService
+	projectService *service.ProjectService
+	auditService   *service.AuditService
+}
+
+func NewMemberHandler(memberService *service.MemberService, projectService *service.ProjectService, auditService *service.AuditService) *MemberHandler {
+	return &MemberHandler{
+		memberService:  memberService,
+		projectService: projectService,
+		auditService:   auditService,
+	}
+}
+
+func (h *MemberHandler) ListMembers(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	members, err := h.memberService.ListMembers(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+func (h *MemberHandler) InviteMember(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.memberService.InviteMember(c.Request.Context(), projectID, userID, req)
+	if err != nil {
+		writeMemberError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditMemberInvite,
+		ProjectID: &projectID,
+		Metadata:  map[string]string{"member_user_id": member.UserID.String(), "role": string(member.Role)},
+	})
+
+	c.JSON(http.StatusCreated, member)
+}
+
+func (h *MemberHandler) UpdateMember(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	member, err := h.memberService.UpdateMemberRole(c.Request.Context(), projectID, memberID, req)
+	if err != nil {
+		writeMemberError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditMemberUpdate,
+		ProjectID: &projectID,
+		Metadata:  map[string]string{"member_user_id": memberID.String(), "role": string(member.Role)},
+	})
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember はメンバーを外します。オーナー以外のメンバーは自分自身を外す（退出する）こともできます
+func (h *MemberHandler) RemoveMember(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	memberID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	// Check permission
+	permission := model.PermissionManage
+	if memberID == userID {
+		permission = model.PermissionRead
+	}
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, permission)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	if err := h.memberService.RemoveMember(c.Request.Context(), projectID, memberID); err != nil {
+		writeMemberError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditMemberRemove,
+		ProjectID: &projectID,
+		Metadata:  map[string]string{"member_user_id": memberID.String()},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func writeMemberError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAlreadyMember):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrOwnerIsImmutable):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -13,12 +14,14 @@ import (
 type NodeHandler struct {
 	nodeService    *service.NodeService
 	projectService *service.ProjectService
+	auditService   *service.AuditService
 }
 
-func NewNodeHandler(nodeService *service.NodeService, projectService *service.ProjectService) *NodeHandler {
+func NewNodeHandler(nodeService *service.NodeService, projectService *service.ProjectService, auditService *service.AuditService) *NodeHandler {
 	return &NodeHandler{
 		nodeService:    nodeService,
 		projectService: projectService,
+		auditService:   auditService,
 	}
 }
 
@@ -35,13 +38,13 @@ func (h *NodeHandler) CreateNode(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
@@ -52,12 +55,13 @@ func (h *NodeHandler) CreateNode(c *gin.Context) {
 		return
 	}
 
-	node, edge, err := h.nodeService.CreateNode(c.Request.Context(), projectID, req)
+	node, edge, err := h.nodeService.CreateNode(c.Request.Context(), userID, projectID, req)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	setETag(c, node.Version)
 	c.JSON(http.StatusOK, gin.H{"node": node, "edge": edge})
 }
 
@@ -80,29 +84,106 @@ func (h *NodeHandler) UpdateNode(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var req model.UpdateNodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.nodeService.UpdateNode(c.Request.Context(), nodeID, req); err != nil {
+	node, err := h.nodeService.UpdateNode(c.Request.Context(), userID, projectID, nodeID, req, expectedVersion)
+	if err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+	setETag(c, node.Version)
+	c.JSON(http.StatusOK, gin.H{"ok": true, "node": node})
+}
+
+// UpdateNodeStatus はノードの状態・期日・重みを置き換えます
+func (h *NodeHandler) UpdateNodeStatus(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req model.UpdateNodeStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	node, err := h.nodeService.UpdateNodeStatus(c.Request.Context(), userID, projectID, nodeID, req, expectedVersion)
+	if err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(c, node.Version)
+	c.JSON(http.StatusOK, gin.H{"ok": true, "node": node})
 }
 
 func (h *NodeHandler) DeleteNode(c *gin.Context) {
@@ -124,21 +205,246 @@ func (h *NodeHandler) DeleteNode(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
-	if err := h.nodeService.DeleteNode(c.Request.Context(), projectID, nodeID); err != nil {
+	expectedVersion, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.nodeService.DeleteNode(c.Request.Context(), userID, projectID, nodeID, expectedVersion); err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditNodeDelete, ProjectID: &projectID, NodeID: &nodeID})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+func (h *NodeHandler) MoveNode(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.MoveNodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.nodeService.MoveNode(c.Request.Context(), userID, projectID, nodeID, req); err != nil {
+		switch {
+		case errors.Is(err, service.ErrNodeNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidMove):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
+
+func (h *NodeHandler) SuggestQuestions(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.QuestionSuggestionsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	suggestions, err := h.nodeService.SuggestQuestions(c.Request.Context(), projectID, nodeID, req.Count)
+	if err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidSuggestionCount) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
+
+func (h *NodeHandler) ExpandNode(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.ExpandNodeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	draft, err := h.nodeService.ExpandNode(c.Request.Context(), projectID, nodeID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+func (h *NodeHandler) AcceptDraft(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.AcceptDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	nodes, edges, err := h.nodeService.AcceptDraft(c.Request.Context(), userID, projectID, nodeID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrNodeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"nodes": nodes, "edges": edges})
+}
@@ -1,21 +1,29 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
 	"github.com/mokuhyo-driven-test/api/internal/model"
 	"github.com/mokuhyo-driven-test/api/internal/service"
 	"github.com/mokuhyo-driven-test/api/pkg/auth"
 )
 
+// maxImportBytes はインポートで受け付けるリクエストボディの上限です
+const maxImportBytes = 5 << 20
+
 type ProjectHandler struct {
 	projectService *service.ProjectService
+	auditService   *service.AuditService
 }
 
-func NewProjectHandler(projectService *service.ProjectService) *ProjectHandler {
-	return &ProjectHandler{projectService: projectService}
+func NewProjectHandler(projectService *service.ProjectService, auditService *service.AuditService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService, auditService: auditService}
 }
 
 func (h *ProjectHandler) CreateProject(c *gin.Context) {
@@ -36,6 +44,7 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditProjectCreate, ProjectID: &project.ID})
 
 	c.JSON(http.StatusOK, gin.H{"project": project})
 }
@@ -69,13 +78,13 @@ func (h *ProjectHandler) GetProject(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
@@ -102,29 +111,61 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionWrite)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
+	expectedRevision, err := parseIfMatch(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	var req model.UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.projectService.UpdateProject(c.Request.Context(), projectID, req); err != nil {
+	// アーカイブの切り替えはオーナーのみ
+	if req.Archived != nil {
+		allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+	}
+
+	revision, err := h.projectService.UpdateProject(c.Request.Context(), projectID, req, expectedRevision)
+	if err != nil {
+		if errors.Is(err, service.ErrVersionConflict) {
+			c.JSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if req.Archived != nil {
+		action := model.AuditProjectUnarchive
+		if *req.Archived {
+			action = model.AuditProjectArchive
+		}
+		recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: action, ProjectID: &projectID})
+	}
 
-	c.JSON(http.StatusOK, gin.H{"ok": true})
+	setETag(c, revision)
+	c.JSON(http.StatusOK, gin.H{"ok": true, "revision": revision})
 }
 
 func (h *ProjectHandler) GetTree(c *gin.Context) {
@@ -140,27 +181,37 @@ func (h *ProjectHandler) GetTree(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
-	tree, err := h.projectService.GetTree(c.Request.Context(), projectID)
+	var tree *model.TreeResponse
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		tree, err = h.projectService.GetTreeByTags(c.Request.Context(), projectID, tags)
+	} else {
+		tree, err = h.projectService.GetTree(c.Request.Context(), projectID)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	setETag(c, tree.Project.Revision)
+	if matchesIfNoneMatch(c, tree.Project.Revision) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 	c.JSON(http.StatusOK, tree)
 }
 
-func (h *ProjectHandler) SaveProject(c *gin.Context) {
+func (h *ProjectHandler) ExportProject(c *gin.Context) {
 	userID, ok := auth.GetUserID(c)
 	if !ok {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
@@ -173,27 +224,184 @@ func (h *ProjectHandler) SaveProject(c *gin.Context) {
 		return
 	}
 
-	// Check ownership
-	owned, err := h.projectService.CheckOwnership(c.Request.Context(), projectID, userID)
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if !owned {
+	if !allowed {
 		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
 		return
 	}
 
-	if err := h.projectService.SaveProject(c.Request.Context(), projectID); err != nil {
+	doc, err := h.projectService.ExportProject(c.Request.Context(), projectID, c.DefaultQuery("format", "markdown"))
+	if err != nil {
+		if errors.Is(err, export.ErrUnsupportedFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	project, err := h.projectService.GetProject(c.Request.Context(), projectID)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, projectID, doc.Extension))
+	c.Data(http.StatusOK, doc.ContentType, doc.Data)
+}
+
+func (h *ProjectHandler) ImportProject(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "failed to read request body", "details": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.ImportProject(c.Request.Context(), userID, format, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrUnsupportedFormat),
+			errors.Is(err, export.ErrInvalidDocument),
+			errors.Is(err, service.ErrInvalidImport):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditProjectCreate,
+		ProjectID: &project.ID,
+		Metadata:  map[string]string{"import_format": format},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+// DuplicateProject はプロジェクトを複製し、リクエストしたユーザーをオーナーとする新しいプロジェクトを作成します
+func (h *ProjectHandler) DuplicateProject(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionRead)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.DuplicateProjectRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	project, err := h.projectService.DuplicateProject(c.Request.Context(), userID, projectID, req)
+	if err != nil {
+		writeDuplicateError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditProjectCreate,
+		ProjectID: &project.ID,
+		Metadata:  map[string]string{"duplicated_from": projectID.String()},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+// ExtractSubtree はノードと子孫を新しいプロジェクトとして切り出します
+func (h *ProjectHandler) ExtractSubtree(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	nodeID, err := uuid.Parse(c.Param("nodeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid node ID"})
+		return
+	}
+
+	var req model.ExtractSubtreeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// Check permission
+	permission := model.PermissionRead
+	if req.DeleteSource {
+		permission = model.PermissionWrite
+	}
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, permission)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	project, err := h.projectService.ExtractSubtree(c.Request.Context(), userID, projectID, nodeID, req)
+	if err != nil {
+		writeDuplicateError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditProjectCreate,
+		ProjectID: &project.ID,
+		Metadata:  map[string]string{"extracted_from": projectID.String(), "node_id": nodeID.String()},
+	})
+	if req.DeleteSource {
+		recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditNodeDelete, ProjectID: &projectID, NodeID: &nodeID})
+	}
 
-	c.JSON(http.StatusOK, gin.H{"saved_at": project.UpdatedAt})
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+func writeDuplicateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound), errors.Is(err, service.ErrNodeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidDuplicate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
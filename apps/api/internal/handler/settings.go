@@ -11,10 +11,11 @@ import (
 
 type SettingsHandler struct {
 	settingsService *service.SettingsService
+	auditService    *service.AuditService
 }
 
-func NewSettingsHandler(settingsService *service.SettingsService) *SettingsHandler {
-	return &SettingsHandler{settingsService: settingsService}
+func NewSettingsHandler(settingsService *service.SettingsService, auditService *service.AuditService) *SettingsHandler {
+	return &SettingsHandler{settingsService: settingsService, auditService: auditService}
 }
 
 func (h *SettingsHandler) GetSettings(c *gin.Context) {
@@ -51,6 +52,7 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.auditService, model.AuditEvent{UserID: userID, Action: model.AuditSettingsUpdate})
 
 	c.JSON(http.StatusOK, gin.H{"ok": true, "settings": settings})
 }
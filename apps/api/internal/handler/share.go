@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+type ShareHandler struct {
+	shareService   *service.ShareService
+	projectService *service.ProjectService
+	auditService   *service.AuditService
+}
+
+func NewShareHandler(shareService *service.ShareService, projectService *service.ProjectService, auditService *service.AuditService) *ShareHandler {
+	return &ShareHandler{
+		shareService:   shareService,
+		projectService: projectService,
+		auditService:   auditService,
+	}
+}
+
+func (h *ShareHandler) CreateShareLink(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var req model.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.shareService.CreateShareLink(c.Request.Context(), projectID, userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditShareLinkCreate,
+		ProjectID: &projectID,
+		Metadata:  map[string]string{"share_link_id": link.ID.String()},
+	})
+
+	c.JSON(http.StatusCreated, link)
+}
+
+func (h *ShareHandler) ListShareLinks(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	links, err := h.shareService.ListShareLinks(c.Request.Context(), projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"share_links": links})
+}
+
+func (h *ShareHandler) RevokeShareLink(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	projectID, err := uuid.Parse(c.Param("projectId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid project ID"})
+		return
+	}
+
+	shareLinkID, err := uuid.Parse(c.Param("shareLinkId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid share link ID"})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), projectID, userID, model.PermissionManage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	if err := h.shareService.RevokeShareLink(c.Request.Context(), projectID, shareLinkID); err != nil {
+		if errors.Is(err, service.ErrShareLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditShareLinkRevoke,
+		ProjectID: &projectID,
+		Metadata:  map[string]string{"share_link_id": shareLinkID.String()},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// GetSharedTree は認証なしで共有トークンからツリーを返します
+func (h *ShareHandler) GetSharedTree(c *gin.Context) {
+	tree, err := h.shareService.GetSharedTree(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, service.ErrShareLinkNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	setETag(c, tree.Project.Revision)
+	if matchesIfNoneMatch(c, tree.Project.Revision) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.JSON(http.StatusOK, tree)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/service"
+	"github.com/mokuhyo-driven-test/api/pkg/auth"
+)
+
+type TemplateHandler struct {
+	templateService *service.TemplateService
+	projectService  *service.ProjectService
+	auditService    *service.AuditService
+}
+
+func NewTemplateHandler(templateService *service.TemplateService, projectService *service.ProjectService, auditService *service.AuditService) *TemplateHandler {
+	return &TemplateHandler{
+		templateService: templateService,
+		projectService:  projectService,
+		auditService:    auditService,
+	}
+}
+
+// ListTemplates は組み込みテンプレートとリクエストしたユーザーが保存したテンプレートを返します
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	templates, err := h.templateService.ListTemplates(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetTemplate はテンプレートをノード付きで返します
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	template, err := h.templateService.GetTemplate(c.Request.Context(), userID, templateID)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// CreateTemplate はプロジェクトをテンプレートとして保存します
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	var req model.CreateTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Check permission
+	allowed, err := h.projectService.CheckPermission(c.Request.Context(), req.ProjectID, userID, model.PermissionRead)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	template, err := h.templateService.CreateTemplate(c.Request.Context(), userID, req)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// DeleteTemplate はリクエストしたユーザーが保存したテンプレートを削除します
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	if err := h.templateService.DeleteTemplate(c.Request.Context(), userID, templateID); err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
+
+// CreateProjectFromTemplate はテンプレートの変数を埋めて新しいプロジェクトを作成します
+func (h *TemplateHandler) CreateProjectFromTemplate(c *gin.Context) {
+	userID, ok := auth.GetUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user ID not found"})
+		return
+	}
+
+	templateID, err := uuid.Parse(c.Param("templateId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid template ID"})
+		return
+	}
+
+	var req model.CreateProjectFromTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	project, err := h.templateService.CreateProjectFromTemplate(c.Request.Context(), userID, templateID, req)
+	if err != nil {
+		writeTemplateError(c, err)
+		return
+	}
+	recordAudit(c, h.auditService, model.AuditEvent{
+		UserID:    userID,
+		Action:    model.AuditProjectCreate,
+		ProjectID: &project.ID,
+		Metadata:  map[string]string{"template_id": templateID.String()},
+	})
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+func writeTemplateError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTemplateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTemplateIsBuiltin):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTemplate), errors.Is(err, service.ErrInvalidImport):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
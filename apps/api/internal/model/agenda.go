@@ -0,0 +1,27 @@
+package model
+
+import "github.com/google/uuid"
+
+// AgendaItem は期日を過ぎた、または期日が近いノードです
+type AgendaItem struct {
+	ProjectID    uuid.UUID `json:"project_id"`
+	ProjectTitle string    `json:"project_title"`
+	Node         Node      `json:"node"`
+	// Path は根から親までの祖先ノードの内容です
+	Path    []string `json:"path"`
+	Overdue bool     `json:"overdue"`
+	// DaysUntilDue は今日から期日までの日数です（期限切れの場合は負の値）
+	DaysUntilDue int `json:"days_until_due"`
+}
+
+type AgendaResponse struct {
+	Today Date         `json:"today"`
+	Days  int          `json:"days"`
+	Items []AgendaItem `json:"items"`
+}
+
+// CalendarFeedResponse は発行直後にだけフィードのトークンを返します
+type CalendarFeedResponse struct {
+	Token string `json:"token"`
+	Path  string `json:"path"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction は監査ログに記録する操作の種類です
+type AuditAction string
+
+const (
+	AuditLogin              AuditAction = "auth.login"
+	AuditProjectCreate      AuditAction = "project.create"
+	AuditProjectArchive     AuditAction = "project.archive"
+	AuditProjectUnarchive   AuditAction = "project.unarchive"
+	AuditNodeDelete         AuditAction = "node.delete"
+	AuditTrashPurge         AuditAction = "trash.purge"
+	AuditShareLinkCreate    AuditAction = "share_link.create"
+	AuditShareLinkRevoke    AuditAction = "share_link.revoke"
+	AuditCalendarFeedCreate AuditAction = "calendar_feed.create"
+	AuditCalendarFeedRevoke AuditAction = "calendar_feed.revoke"
+	AuditMemberInvite       AuditAction = "member.invite"
+	AuditMemberUpdate       AuditAction = "member.update"
+	AuditMemberRemove       AuditAction = "member.remove"
+	AuditSettingsUpdate     AuditAction = "settings.update"
+)
+
+// AuditEvent は監査ログの1件です。UserID は操作したユーザーです
+type AuditEvent struct {
+	ID        uuid.UUID         `json:"id"`
+	Seq       int64             `json:"seq"`
+	UserID    uuid.UUID         `json:"user_id"`
+	Action    AuditAction       `json:"action"`
+	ProjectID *uuid.UUID        `json:"project_id,omitempty"`
+	NodeID    *uuid.UUID        `json:"node_id,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	IPAddress string            `json:"ip_address"`
+	UserAgent string            `json:"user_agent"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditListResponse は新しい順の監査ログです。NextBefore を before に指定すると続きを取得できます
+type AuditListResponse struct {
+	Events     []AuditEvent `json:"events"`
+	NextBefore *int64       `json:"next_before,omitempty"`
+}
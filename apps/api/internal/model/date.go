@@ -0,0 +1,57 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DateLayout は日付（時刻なし）の JSON 表現です
+const DateLayout = "2006-01-02"
+
+// Date は時刻を持たない日付です。JSON では "2006-01-02"、DB では date 型として扱います
+type Date struct {
+	time.Time
+}
+
+// NewDate は t の年月日から Date を作成します
+func NewDate(t time.Time) Date {
+	return Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+}
+
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("date must be a string in YYYY-MM-DD format")
+	}
+	t, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return fmt.Errorf("date must be in YYYY-MM-DD format")
+	}
+	d.Time = t
+	return nil
+}
+
+// Scan は database/sql.Scanner の実装です
+func (d *Date) Scan(src interface{}) error {
+	t, ok := src.(time.Time)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into Date", src)
+	}
+	*d = NewDate(t)
+	return nil
+}
+
+// Value は database/sql/driver.Valuer の実装です
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
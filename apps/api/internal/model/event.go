@@ -0,0 +1,82 @@
+package model
+
+import "github.com/google/uuid"
+
+// NodeEvent は node.created / node.updated イベントのデータです
+type NodeEvent struct {
+	Node Node  `json:"node"`
+	Edge *Edge `json:"edge,omitempty"`
+}
+
+// NodeRefEvent は node.deleted / node.restored イベントのデータです（子孫も対象です）
+type NodeRefEvent struct {
+	NodeID uuid.UUID `json:"node_id"`
+}
+
+// NodeMovedEvent は node.moved イベントのデータです
+// Edges には移動元と移動先の兄弟を含む、並び順が変わったエッジがすべて入ります
+type NodeMovedEvent struct {
+	NodeID       uuid.UUID `json:"node_id"`
+	ParentNodeID uuid.UUID `json:"parent_node_id"`
+	Edges        []Edge    `json:"edges"`
+}
+
+// NodesCreatedEvent は nodes.created イベント（部分木の一括作成）のデータです
+type NodesCreatedEvent struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// EdgeEvent は edge.updated イベントのデータです
+type EdgeEvent struct {
+	Edge Edge `json:"edge"`
+}
+
+// ReorderedEvent は edges.reordered イベントのデータです
+type ReorderedEvent struct {
+	ParentNodeID        *uuid.UUID  `json:"parent_node_id"`
+	OrderedChildNodeIDs []uuid.UUID `json:"ordered_child_node_ids"`
+}
+
+// ProjectEvent は project.updated イベントのデータです
+type ProjectEvent struct {
+	Project Project `json:"project"`
+}
+
+// TreeRestoredEvent は tree.restored イベント（スナップショットからの復元）のデータです
+type TreeRestoredEvent struct {
+	Version int `json:"version"`
+}
+
+// TagEvent は tag.created / tag.updated イベントのデータです
+type TagEvent struct {
+	Tag Tag `json:"tag"`
+}
+
+// TagRefEvent は tag.deleted イベントのデータです（ノードからも外れます）
+type TagRefEvent struct {
+	TagID uuid.UUID `json:"tag_id"`
+}
+
+// NodeTagsEvent は node.tags_updated イベントのデータです
+type NodeTagsEvent struct {
+	NodeID uuid.UUID   `json:"node_id"`
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+// QuestionReadyEvent は生成が終わった質問を通知するイベントのデータです（Node は保存後のバージョンを含みます）
+type QuestionReadyEvent struct {
+	NodeID   uuid.UUID `json:"node_id"`
+	Question string    `json:"question"`
+	Node     Node      `json:"node"`
+}
+
+// OperationEvent は operation.undone / operation.redone イベントのデータです（クライアントはツリーを読み直します）
+type OperationEvent struct {
+	Operation Operation `json:"operation"`
+}
+
+// QuestionPendingEvent は購読開始時点で生成中の質問を持つノードの一覧です
+type QuestionPendingEvent struct {
+	NodeIDs []uuid.UUID `json:"node_ids"`
+}
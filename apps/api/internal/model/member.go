@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectRole はプロジェクトメンバーの役割です
+type ProjectRole string
+
+const (
+	RoleOwner  ProjectRole = "owner"
+	RoleEditor ProjectRole = "editor"
+	RoleViewer ProjectRole = "viewer"
+)
+
+// Permission はプロジェクトに対する操作の種類です
+type Permission int
+
+const (
+	// PermissionRead はツリーの閲覧・エクスポート・購読です
+	PermissionRead Permission = iota
+	// PermissionWrite はノード・エッジの編集やスナップショットの保存・復元です
+	PermissionWrite
+	// PermissionManage はメンバー管理やアーカイブなど、オーナーだけが行える操作です
+	PermissionManage
+)
+
+// Allows は役割が指定した操作を許可するかを返します
+func (r ProjectRole) Allows(permission Permission) bool {
+	switch r {
+	case RoleOwner:
+		return true
+	case RoleEditor:
+		return permission <= PermissionWrite
+	case RoleViewer:
+		return permission == PermissionRead
+	default:
+		return false
+	}
+}
+
+type ProjectMember struct {
+	ProjectID uuid.UUID   `json:"project_id"`
+	UserID    uuid.UUID   `json:"user_id"`
+	Email     string      `json:"email"`
+	Name      string      `json:"name"`
+	Picture   *string     `json:"picture,omitempty"`
+	Role      ProjectRole `json:"role"`
+	InvitedBy *uuid.UUID  `json:"invited_by,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+type InviteMemberRequest struct {
+	Email string      `json:"email" binding:"required,email"`
+	Role  ProjectRole `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type UpdateMemberRequest struct {
+	Role ProjectRole `json:"role" binding:"required,oneof=editor viewer"`
+}
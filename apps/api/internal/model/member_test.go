@@ -0,0 +1,32 @@
+package model
+
+import "testing"
+
+func TestProjectRoleAllows(t *testing.T) {
+	tests := []struct {
+		role   ProjectRole
+		read   bool
+		write  bool
+		manage bool
+	}{
+		{role: RoleOwner, read: true, write: true, manage: true},
+		{role: RoleEditor, read: true, write: true, manage: false},
+		{role: RoleViewer, read: true, write: false, manage: false},
+		{role: "", read: false, write: false, manage: false},
+		{role: "admin", read: false, write: false, manage: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			for permission, want := range map[Permission]bool{
+				PermissionRead:   tt.read,
+				PermissionWrite:  tt.write,
+				PermissionManage: tt.manage,
+			} {
+				if got := tt.role.Allows(permission); got != want {
+					t.Errorf("%q.Allows(%d) = %v, want %v", tt.role, permission, got, want)
+				}
+			}
+		})
+	}
+}
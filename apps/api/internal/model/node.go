@@ -7,15 +7,41 @@ import (
 )
 
 type Node struct {
-	ID        uuid.UUID  `json:"id"`
-	ProjectID uuid.UUID  `json:"project_id"`
-	Content   string     `json:"content"`
-	Question  *string    `json:"question,omitempty"`
-	CreatedAt time.Time  `json:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ID        uuid.UUID `json:"id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Content   string    `json:"content"`
+	Question  *string   `json:"question,omitempty"`
+	// QuestionStatus は質問をバックグラウンドで生成中のときだけ "pending" になります
+	QuestionStatus QuestionStatus `json:"question_status,omitempty"`
+	Status         NodeStatus     `json:"status"`
+	DueDate        *Date          `json:"due_date,omitempty"`
+	Weight         *float64       `json:"weight,omitempty"`
+	Version        int64          `json:"version"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      *time.Time     `json:"deleted_at,omitempty"`
+	// Progress は GET /tree でだけ設定される、子孫から積み上げた達成率（0〜1）です（保存されません）
+	Progress *float64 `json:"progress,omitempty"`
+	// TagIDs は GET /tree でだけ設定される、ノードに付いたタグです
+	TagIDs []uuid.UUID `json:"tag_ids,omitempty"`
 }
 
+// NodeStatus は目標としてのノードの状態です
+type NodeStatus string
+
+const (
+	NodeStatusTodo    NodeStatus = "todo"
+	NodeStatusDoing   NodeStatus = "doing"
+	NodeStatusDone    NodeStatus = "done"
+	NodeStatusDropped NodeStatus = "dropped"
+)
+
+type QuestionStatus string
+
+const (
+	QuestionStatusPending QuestionStatus = "pending"
+)
+
 type CreateNodeRequest struct {
 	Content       string     `json:"content" binding:"max=200"`
 	ParentNodeID  *uuid.UUID `json:"parent_node_id"`
@@ -28,3 +54,56 @@ type CreateNodeRequest struct {
 type UpdateNodeRequest struct {
 	Content string `json:"content" binding:"max=200"`
 }
+
+// UpdateNodeStatusRequest はノードの進捗をまとめて置き換えます（due_date / weight を省略すると未設定に戻ります）
+type UpdateNodeStatusRequest struct {
+	Status  NodeStatus `json:"status" binding:"required,oneof=todo doing done dropped"`
+	DueDate *Date      `json:"due_date,omitempty"`
+	Weight  *float64   `json:"weight,omitempty" binding:"omitempty,gt=0,lte=100"`
+}
+
+type MoveNodeRequest struct {
+	ParentNodeID uuid.UUID `json:"parent_node_id" binding:"required"`
+	OrderIndex   *int      `json:"order_index,omitempty"`
+}
+
+// TrashEntry は1回の削除操作で論理削除されたノード群を表します
+type TrashEntry struct {
+	DeletionID uuid.UUID `json:"deletion_id"`
+	DeletedAt  time.Time `json:"deleted_at"`
+	Node       Node      `json:"node"`
+	NodeCount  int       `json:"node_count"`
+}
+
+// QuestionSuggestionsRequest は質問候補の件数の指定です（省略時は観点の数の4件、最大12件）
+type QuestionSuggestionsRequest struct {
+	Count int `json:"count,omitempty" binding:"omitempty,min=1,max=12"`
+}
+
+type QuestionSuggestion struct {
+	Question string       `json:"question"`
+	Relation RelationType `json:"relation"`
+}
+
+// DraftNode はまだ保存されていない子ノード案です
+type DraftNode struct {
+	Content       string       `json:"content" binding:"max=200"`
+	Question      *string      `json:"question,omitempty" binding:"omitempty,max=30"`
+	Relation      RelationType `json:"relation"`
+	RelationLabel *string      `json:"relation_label,omitempty" binding:"omitempty,max=20"`
+	Children      []DraftNode  `json:"children,omitempty" binding:"omitempty,dive"`
+}
+
+type ExpandNodeRequest struct {
+	Depth   int `json:"depth,omitempty" binding:"omitempty,min=1,max=3"`
+	Breadth int `json:"breadth,omitempty" binding:"omitempty,min=1,max=5"`
+}
+
+type ExpandNodeResponse struct {
+	ParentNodeID uuid.UUID   `json:"parent_node_id"`
+	Children     []DraftNode `json:"children"`
+}
+
+type AcceptDraftRequest struct {
+	Children []DraftNode `json:"children" binding:"required,min=1,dive"`
+}
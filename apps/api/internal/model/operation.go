@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperationKind は取り消し・やり直しの対象になる操作の種類です
+type OperationKind string
+
+const (
+	OperationNodeCreate  OperationKind = "node.create"
+	OperationNodesCreate OperationKind = "nodes.create"
+	OperationNodeUpdate  OperationKind = "node.update"
+	OperationNodeRevert  OperationKind = "node.revert"
+	OperationNodeStatus  OperationKind = "node.status"
+	OperationNodeDelete  OperationKind = "node.delete"
+	OperationNodeRestore OperationKind = "node.restore"
+	OperationNodeMove    OperationKind = "node.move"
+	OperationEdgeUpdate  OperationKind = "edge.update"
+	OperationReorder     OperationKind = "edges.reorder"
+)
+
+// OperationState は操作履歴のカーソル上の位置です
+type OperationState string
+
+const (
+	OperationDone      OperationState = "done"
+	OperationUndone    OperationState = "undone"
+	OperationDiscarded OperationState = "discarded"
+)
+
+// Operation は操作履歴の1件です。Before / After には操作の種類に応じた項目だけが入ります
+type Operation struct {
+	ID        uuid.UUID      `json:"id"`
+	Seq       int64          `json:"seq"`
+	ProjectID uuid.UUID      `json:"project_id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Kind      OperationKind  `json:"kind"`
+	NodeIDs   []uuid.UUID    `json:"node_ids,omitempty"`
+	Before    OperationValue `json:"before"`
+	After     OperationValue `json:"after"`
+	State     OperationState `json:"state"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// OperationValue は操作の前後の状態です
+type OperationValue struct {
+	Content             *string       `json:"content,omitempty"`
+	Question            *string       `json:"question,omitempty"`
+	Status              *NodeStatus   `json:"status,omitempty"`
+	DueDate             *Date         `json:"due_date,omitempty"`
+	Weight              *float64      `json:"weight,omitempty"`
+	EdgeID              *uuid.UUID    `json:"edge_id,omitempty"`
+	Relation            *RelationType `json:"relation,omitempty"`
+	RelationLabel       *string       `json:"relation_label,omitempty"`
+	ParentNodeID        *uuid.UUID    `json:"parent_node_id,omitempty"`
+	OrderedChildNodeIDs []uuid.UUID   `json:"ordered_child_node_ids,omitempty"`
+}
+
+type HistoryResponse struct {
+	OK        bool      `json:"ok"`
+	Operation Operation `json:"operation"`
+	Revision  int64     `json:"revision"`
+}
@@ -11,9 +11,12 @@ type Project struct {
 	UserID      uuid.UUID  `json:"user_id"`
 	Title       string     `json:"title"`
 	Description *string    `json:"description,omitempty"`
+	Revision    int64      `json:"revision"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	// Role は一覧取得時の、リクエストしたユーザーの役割です
+	Role ProjectRole `json:"role,omitempty"`
 }
 
 type CreateProjectRequest struct {
@@ -26,3 +29,18 @@ type UpdateProjectRequest struct {
 	Description *string `json:"description,omitempty"`
 	Archived    *bool   `json:"archived,omitempty"`
 }
+
+// DuplicateProjectRequest は複製して作成するプロジェクトの指定です（Title を省略すると元のタイトルから付けます）
+type DuplicateProjectRequest struct {
+	Title       *string `json:"title,omitempty" binding:"omitempty,min=3,max=20"`
+	Description *string `json:"description,omitempty"`
+	// ResetProgress は状態を todo に戻し、期日を外して複製します
+	ResetProgress bool `json:"reset_progress,omitempty"`
+}
+
+// ExtractSubtreeRequest はノードと子孫を切り出して作成するプロジェクトの指定です
+type ExtractSubtreeRequest struct {
+	DuplicateProjectRequest
+	// DeleteSource は切り出したノードを元のプロジェクトから削除（ゴミ箱へ移動）します
+	DeleteSource bool `json:"delete_source,omitempty"`
+}
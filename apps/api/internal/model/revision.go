@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NodeRevision はノードの内容・質問が変わるたびに保存される版です（Version は保存時のノードのバージョン）
+type NodeRevision struct {
+	ID        uuid.UUID `json:"id"`
+	NodeID    uuid.UUID `json:"node_id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Version   int64     `json:"version"`
+	Content   string    `json:"content"`
+	Question  *string   `json:"question,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// ContentDiff / QuestionDiff は1つ前の版からの差分です（最初の版はすべて insert）
+	ContentDiff  []DiffSegment `json:"content_diff"`
+	QuestionDiff []DiffSegment `json:"question_diff"`
+}
+
+// DiffOp は差分の区間の種類です
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffSegment は文字単位の差分の1区間です
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// NodeHistoryResponse は新しい順のノードの版の一覧です
+type NodeHistoryResponse struct {
+	NodeID    uuid.UUID      `json:"node_id"`
+	Revisions []NodeRevision `json:"revisions"`
+}
@@ -0,0 +1,47 @@
+package model
+
+import "github.com/google/uuid"
+
+type SearchHitKind string
+
+const (
+	SearchHitProject SearchHitKind = "project"
+	SearchHitNode    SearchHitKind = "node"
+)
+
+// SearchCandidate は検索語にすべて一致したプロジェクトまたはノードです（スニペットはサービスで作ります）
+type SearchCandidate struct {
+	ProjectID          uuid.UUID
+	ProjectTitle       string
+	ProjectDescription *string
+	NodeID             *uuid.UUID
+	Content            string
+	Question           *string
+	Breadcrumb         []string
+	Score              float64
+}
+
+type SearchHit struct {
+	Kind         SearchHitKind `json:"kind"`
+	ProjectID    uuid.UUID     `json:"project_id"`
+	ProjectTitle string        `json:"project_title"`
+	NodeID       *uuid.UUID    `json:"node_id,omitempty"`
+	// Field は一致したフィールドです（title / description / content / question）
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+	// Highlights は Snippet 内で検索語に一致した範囲です（文字単位のオフセット）
+	Highlights []SearchHighlight `json:"highlights"`
+	// Breadcrumb は根から親までの祖先ノードの内容です
+	Breadcrumb []string `json:"breadcrumb,omitempty"`
+	Score      float64  `json:"score"`
+}
+
+type SearchHighlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+type SearchResponse struct {
+	Query string      `json:"query"`
+	Hits  []SearchHit `json:"hits"`
+}
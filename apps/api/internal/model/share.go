@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink はログインなしでツリーを閲覧できる共有リンクです（トークン自体は保存しません）
+type ShareLink struct {
+	ID        uuid.UUID  `json:"id"`
+	ProjectID uuid.UUID  `json:"project_id"`
+	Label     *string    `json:"label,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type CreateShareLinkRequest struct {
+	Label         *string `json:"label,omitempty" binding:"omitempty,max=50"`
+	ExpiresInDays *int    `json:"expires_in_days,omitempty" binding:"omitempty,min=1,max=365"`
+}
+
+// CreateShareLinkResponse は作成直後にだけトークンを返します
+type CreateShareLinkResponse struct {
+	ShareLink
+	Token string `json:"token"`
+}
+
+// SharedProject は共有リンク向けの、ユーザー識別子を含まないプロジェクトです
+type SharedProject struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	Revision    int64      `json:"revision"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+}
+
+type SharedTreeResponse struct {
+	Project SharedProject `json:"project"`
+	Nodes   []Node        `json:"nodes"`
+	Edges   []Edge        `json:"edges"`
+}
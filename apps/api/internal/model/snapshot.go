@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Snapshot struct {
+	ID        uuid.UUID     `json:"id"`
+	ProjectID uuid.UUID     `json:"project_id"`
+	Version   int           `json:"version"`
+	Payload   *TreeResponse `json:"payload,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type SnapshotDiff struct {
+	FromVersion  int        `json:"from_version"`
+	ToVersion    int        `json:"to_version"`
+	AddedNodes   []Node     `json:"added_nodes"`
+	RemovedNodes []Node     `json:"removed_nodes"`
+	ChangedNodes []NodeDiff `json:"changed_nodes"`
+	ChangedEdges []EdgeDiff `json:"changed_edges"`
+}
+
+type NodeDiff struct {
+	NodeID uuid.UUID `json:"node_id"`
+	Before Node      `json:"before"`
+	After  Node      `json:"after"`
+}
+
+type EdgeDiff struct {
+	ChildNodeID uuid.UUID `json:"child_node_id"`
+	Before      Edge      `json:"before"`
+	After       Edge      `json:"after"`
+}
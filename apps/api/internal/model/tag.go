@@ -0,0 +1,38 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag はプロジェクトごとに定義するノードのラベルです
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	ProjectID uuid.UUID `json:"project_id"`
+	Name      string    `json:"name"`
+	Color     string    `json:"color"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NodeTag はノードとタグの対応です
+type NodeTag struct {
+	NodeID uuid.UUID `json:"node_id"`
+	TagID  uuid.UUID `json:"tag_id"`
+}
+
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,min=1,max=30"`
+	Color string `json:"color" binding:"required,hexcolor,len=7"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name,omitempty" binding:"omitempty,min=1,max=30"`
+	Color *string `json:"color,omitempty" binding:"omitempty,hexcolor,len=7"`
+}
+
+// SetNodeTagsRequest はノードのタグをまとめて置き換えます（空配列ですべて外します）
+type SetNodeTagsRequest struct {
+	TagIDs []uuid.UUID `json:"tag_ids" binding:"required"`
+}
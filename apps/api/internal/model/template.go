@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Template はプロジェクトの構造（ノードの内容・問い・関係）を再利用するためのテンプレートです
+// 内容や問いに含まれる {{期限}} のようなプレースホルダーは、プロジェクトの作成時に値で置き換えます
+type Template struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	Title       string     `json:"title"`
+	Description *string    `json:"description,omitempty"`
+	// Builtin はサーバーに同梱された組み込みテンプレートかどうかです（組み込みは削除できません）
+	Builtin   bool           `json:"builtin"`
+	Variables []string       `json:"variables"`
+	NodeCount int            `json:"node_count"`
+	Nodes     []TemplateNode `json:"nodes,omitempty"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+}
+
+// TemplateNode はテンプレートの1ノードです。Relation は親との関係です（最上位のノードでは無視されます）
+type TemplateNode struct {
+	Content       string         `json:"content"`
+	Question      *string        `json:"question,omitempty"`
+	Relation      RelationType   `json:"relation,omitempty"`
+	RelationLabel *string        `json:"relation_label,omitempty"`
+	Weight        *float64       `json:"weight,omitempty"`
+	Children      []TemplateNode `json:"children,omitempty"`
+}
+
+// CreateTemplateRequest はプロジェクトをテンプレートとして保存する指定です（Title を省略するとプロジェクトのタイトルを使います）
+type CreateTemplateRequest struct {
+	ProjectID   uuid.UUID `json:"project_id" binding:"required"`
+	Title       *string   `json:"title,omitempty" binding:"omitempty,min=1,max=50"`
+	Description *string   `json:"description,omitempty"`
+}
+
+// CreateProjectFromTemplateRequest はテンプレートから作成するプロジェクトの指定です
+// Title を省略すると、変数を埋めた最初のノードの内容から付けます
+type CreateProjectFromTemplateRequest struct {
+	Title       *string           `json:"title,omitempty" binding:"omitempty,min=3,max=20"`
+	Description *string           `json:"description,omitempty"`
+	Variables   map[string]string `json:"variables,omitempty"`
+}
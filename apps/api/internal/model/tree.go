@@ -4,4 +4,6 @@ type TreeResponse struct {
 	Project Project `json:"project"`
 	Nodes   []Node  `json:"nodes"`
 	Edges   []Edge  `json:"edges"`
+	// Tags は GET /tree でだけ設定される、プロジェクトのタグ定義です
+	Tags []Tag `json:"tags,omitempty"`
 }
@@ -37,6 +37,9 @@ type TxInterface interface {
 	// Exec はトランザクション内でINSERT、UPDATE、DELETEなどのクエリを実行します
 	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
 
+	// Begin はセーブポイントとして入れ子のトランザクションを開始します
+	Begin(ctx context.Context) (TxInterface, error)
+
 	// Commit はトランザクションをコミットします
 	Commit(ctx context.Context) error
 
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// auditRepository は監査ログリポジトリのPostgreSQL実装です
+type auditRepository struct {
+	db repository.DBInterface
+}
+
+// NewAuditRepository は新しい監査ログリポジトリを作成します
+func NewAuditRepository(db repository.DBInterface) repository.AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event model.AuditEvent) error {
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit metadata: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO audit_events (user_id, action, project_id, node_id, metadata, ip_address, user_agent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, event.UserID, event.Action, event.ProjectID, event.NodeID, data, event.IPAddress, event.UserAgent)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+	return nil
+}
+
+// ListByUserID はユーザー自身の監査ログを新しい順に返します（before を指定するとそれより古いものだけ）
+func (r *auditRepository) ListByUserID(ctx context.Context, userID uuid.UUID, before *int64, limit int) ([]model.AuditEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, seq, user_id, action, project_id, node_id, metadata, ip_address, user_agent, created_at
+		FROM audit_events
+		WHERE user_id = $1 AND ($2::bigint IS NULL OR seq < $2)
+		ORDER BY seq DESC
+		LIMIT $3
+	`, userID, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []model.AuditEvent
+	for rows.Next() {
+		var e model.AuditEvent
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.Seq, &e.UserID, &e.Action, &e.ProjectID, &e.NodeID,
+			&data, &e.IPAddress, &e.UserAgent, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		if err := json.Unmarshal(data, &e.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit metadata: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
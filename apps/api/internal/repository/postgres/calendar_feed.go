@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// calendarFeedRepository はカレンダーフィードのトークンリポジトリのPostgreSQL実装です
+type calendarFeedRepository struct {
+	db repository.DBInterface
+}
+
+// NewCalendarFeedRepository は新しいカレンダーフィードのトークンリポジトリを作成します
+func NewCalendarFeedRepository(db repository.DBInterface) repository.CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+// Upsert はユーザーのフィードのトークンを設定します（既存のトークンは無効になります）
+func (r *calendarFeedRepository) Upsert(ctx context.Context, userID uuid.UUID, tokenHash string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO calendar_feeds (user_id, token_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET token_hash = EXCLUDED.token_hash, created_at = NOW()
+	`, userID, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to upsert calendar feed: %w", err)
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) GetUserIDByTokenHash(ctx context.Context, tokenHash string) (*uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id FROM calendar_feeds WHERE token_hash = $1
+	`, tokenHash).Scan(&userID)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar feed: %w", err)
+	}
+	return &userID, nil
+}
+
+func (r *calendarFeedRepository) Delete(ctx context.Context, userID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM calendar_feeds WHERE user_id = $1
+	`, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete calendar feed: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
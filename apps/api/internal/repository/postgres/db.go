@@ -90,6 +90,15 @@ func (tx *PostgresTx) Exec(ctx context.Context, sql string, args ...interface{})
 	return tx.tx.Exec(ctx, sql, args...)
 }
 
+// Begin はセーブポイントとして入れ子のトランザクションを開始します
+func (tx *PostgresTx) Begin(ctx context.Context) (repository.TxInterface, error) {
+	nested, err := tx.tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresTx{tx: nested}, nil
+}
+
 // Commit はトランザクションをコミットします
 func (tx *PostgresTx) Commit(ctx context.Context) error {
 	return tx.tx.Commit(ctx)
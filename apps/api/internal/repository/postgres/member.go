@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// memberRepository はプロジェクトメンバーリポジトリのPostgreSQL実装です
+type memberRepository struct {
+	db repository.DBInterface
+}
+
+// NewMemberRepository は新しいプロジェクトメンバーリポジトリを作成します
+func NewMemberRepository(db repository.DBInterface) repository.MemberRepository {
+	return &memberRepository{db: db}
+}
+
+func (r *memberRepository) Add(ctx context.Context, projectID, userID uuid.UUID, role model.ProjectRole, invitedBy *uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO project_members (project_id, user_id, role, invited_by)
+		VALUES ($1, $2, $3, $4)
+	`, projectID, userID, role, invitedBy)
+	if err != nil {
+		return fmt.Errorf("failed to add project member: %w", err)
+	}
+	return nil
+}
+
+func (r *memberRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.ProjectMember, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT m.project_id, m.user_id, u.email, u.name, u.picture, m.role, m.invited_by, m.created_at, m.updated_at
+		FROM project_members m
+		INNER JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1
+		ORDER BY CASE m.role WHEN 'owner' THEN 0 WHEN 'editor' THEN 1 ELSE 2 END, m.created_at
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list project members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []model.ProjectMember
+	for rows.Next() {
+		var m model.ProjectMember
+		if err := rows.Scan(&m.ProjectID, &m.UserID, &m.Email, &m.Name, &m.Picture, &m.Role,
+			&m.InvitedBy, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan project member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// GetRole はユーザーの役割を返します（メンバーでない場合は nil）
+func (r *memberRepository) GetRole(ctx context.Context, projectID, userID uuid.UUID) (*model.ProjectRole, error) {
+	var role model.ProjectRole
+	err := r.db.QueryRow(ctx, `
+		SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2
+	`, projectID, userID).Scan(&role)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project role: %w", err)
+	}
+	return &role, nil
+}
+
+func (r *memberRepository) UpdateRole(ctx context.Context, projectID, userID uuid.UUID, role model.ProjectRole) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE project_members SET role = $1, updated_at = NOW()
+		WHERE project_id = $2 AND user_id = $3
+	`, role, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update project member: %w", err)
+	}
+	return nil
+}
+
+func (r *memberRepository) Remove(ctx context.Context, projectID, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		DELETE FROM project_members WHERE project_id = $1 AND user_id = $2
+	`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove project member: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// nodeRevisionRepository はノードの編集履歴リポジトリのPostgreSQL実装です
+type nodeRevisionRepository struct {
+	db repository.DBInterface
+}
+
+// NewNodeRevisionRepository は新しいノードの編集履歴リポジトリを作成します
+func NewNodeRevisionRepository(db repository.DBInterface) repository.NodeRevisionRepository {
+	return &nodeRevisionRepository{db: db}
+}
+
+// ListByNodeID はノードの版を新しい順に返します
+func (r *nodeRevisionRepository) ListByNodeID(ctx context.Context, nodeID uuid.UUID) ([]model.NodeRevision, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, node_id, project_id, version, content, question, created_at
+		FROM node_revisions
+		WHERE node_id = $1
+		ORDER BY seq DESC
+	`, nodeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []model.NodeRevision
+	for rows.Next() {
+		var rev model.NodeRevision
+		if err := rows.Scan(&rev.ID, &rev.NodeID, &rev.ProjectID, &rev.Version,
+			&rev.Content, &rev.Question, &rev.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan node revision: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+func (r *nodeRevisionRepository) GetByID(ctx context.Context, revisionID uuid.UUID) (*model.NodeRevision, error) {
+	var rev model.NodeRevision
+	err := r.db.QueryRow(ctx, `
+		SELECT id, node_id, project_id, version, content, question, created_at
+		FROM node_revisions
+		WHERE id = $1
+	`, revisionID).Scan(&rev.ID, &rev.NodeID, &rev.ProjectID, &rev.Version,
+		&rev.Content, &rev.Question, &rev.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node revision: %w", err)
+	}
+	return &rev, nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// operationRepository は操作履歴リポジトリのPostgreSQL実装です
+type operationRepository struct {
+	db repository.DBInterface
+}
+
+// NewOperationRepository は新しい操作履歴リポジトリを作成します
+func NewOperationRepository(db repository.DBInterface) repository.OperationRepository {
+	return &operationRepository{db: db}
+}
+
+// operationPayload は operations.payload に保存する JSON です
+type operationPayload struct {
+	NodeIDs []uuid.UUID          `json:"node_ids,omitempty"`
+	Before  model.OperationValue `json:"before"`
+	After   model.OperationValue `json:"after"`
+}
+
+func (r *operationRepository) Append(ctx context.Context, op model.Operation) error {
+	payload, err := json.Marshal(operationPayload{NodeIDs: op.NodeIDs, Before: op.Before, After: op.After})
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation payload: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO operations (project_id, user_id, kind, payload)
+		VALUES ($1, $2, $3, $4)
+	`, op.ProjectID, op.UserID, op.Kind, payload)
+	if err != nil {
+		return fmt.Errorf("failed to append operation: %w", err)
+	}
+	return nil
+}
+
+// DiscardUndone はユーザーが取り消した操作をやり直せないようにします（新しい操作を記録する前に呼びます）
+func (r *operationRepository) DiscardUndone(ctx context.Context, projectID, userID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE operations SET state = 'discarded', updated_at = NOW()
+		WHERE project_id = $1 AND user_id = $2 AND state = 'undone'
+	`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to discard undone operations: %w", err)
+	}
+	return nil
+}
+
+// GetLastDone はユーザーが最後に行った、まだ取り消していない操作をロックして返します（ない場合は nil）
+func (r *operationRepository) GetLastDone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error) {
+	return r.getOne(ctx, `
+		SELECT id, seq, project_id, user_id, kind, payload, state, created_at, updated_at
+		FROM operations
+		WHERE project_id = $1 AND user_id = $2 AND state = 'done'
+		ORDER BY seq DESC
+		LIMIT 1
+		FOR UPDATE
+	`, projectID, userID)
+}
+
+// GetFirstUndone はユーザーが最後に取り消した操作をロックして返します（ない場合は nil）
+// 新しい操作で取り消し済みの操作は破棄されるため、undone の操作は常に末尾に連続して並びます
+func (r *operationRepository) GetFirstUndone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error) {
+	return r.getOne(ctx, `
+		SELECT id, seq, project_id, user_id, kind, payload, state, created_at, updated_at
+		FROM operations
+		WHERE project_id = $1 AND user_id = $2 AND state = 'undone'
+		ORDER BY seq ASC
+		LIMIT 1
+		FOR UPDATE
+	`, projectID, userID)
+}
+
+func (r *operationRepository) getOne(ctx context.Context, query string, args ...interface{}) (*model.Operation, error) {
+	var op model.Operation
+	var data []byte
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&op.ID, &op.Seq, &op.ProjectID, &op.UserID, &op.Kind, &data, &op.State, &op.CreatedAt, &op.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation: %w", err)
+	}
+
+	var payload operationPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal operation payload: %w", err)
+	}
+	op.NodeIDs, op.Before, op.After = payload.NodeIDs, payload.Before, payload.After
+	return &op, nil
+}
+
+func (r *operationRepository) SetState(ctx context.Context, operationID uuid.UUID, state model.OperationState) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE operations SET state = $1, updated_at = NOW() WHERE id = $2
+	`, state, operationID)
+	if err != nil {
+		return fmt.Errorf("failed to update operation state: %w", err)
+	}
+	return nil
+}
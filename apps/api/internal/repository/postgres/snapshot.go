@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// snapshotRepository はスナップショットリポジトリのPostgreSQL実装です
+type snapshotRepository struct {
+	db repository.DBInterface
+}
+
+// NewSnapshotRepository は新しいスナップショットリポジトリを作成します
+func NewSnapshotRepository(db repository.DBInterface) repository.SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+func (r *snapshotRepository) Create(ctx context.Context, projectID uuid.UUID, payload model.TreeResponse) (*model.Snapshot, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot payload: %w", err)
+	}
+
+	var snapshot model.Snapshot
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO snapshots (project_id, version, payload)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2
+		FROM snapshots
+		WHERE project_id = $1
+		RETURNING id, project_id, version, created_at
+	`, projectID, data).Scan(
+		&snapshot.ID, &snapshot.ProjectID, &snapshot.Version, &snapshot.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot: %w", err)
+	}
+	snapshot.Payload = &payload
+	return &snapshot, nil
+}
+
+func (r *snapshotRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Snapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, version, created_at
+		FROM snapshots
+		WHERE project_id = $1
+		ORDER BY version DESC
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []model.Snapshot
+	for rows.Next() {
+		var s model.Snapshot
+		if err := rows.Scan(&s.ID, &s.ProjectID, &s.Version, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot: %w", err)
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
+
+func (r *snapshotRepository) GetByVersion(ctx context.Context, projectID uuid.UUID, version int) (*model.Snapshot, error) {
+	var snapshot model.Snapshot
+	var data []byte
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, version, payload, created_at
+		FROM snapshots
+		WHERE project_id = $1 AND version = $2
+	`, projectID, version).Scan(
+		&snapshot.ID, &snapshot.ProjectID, &snapshot.Version, &data, &snapshot.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot: %w", err)
+	}
+
+	var payload model.TreeResponse
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot payload: %w", err)
+	}
+	snapshot.Payload = &payload
+	return &snapshot, nil
+}
+
+// RestoreTree はプロジェクトのノードとエッジをスナップショットの状態に戻します
+// スナップショットに含まれないノードは論理削除され、ゴミ箱から復元できる状態で残ります
+func (r *snapshotRepository) RestoreTree(ctx context.Context, projectID uuid.UUID, nodes []model.Node, edges []model.Edge) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	keepIDs := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		keepIDs = append(keepIDs, n.ID.String())
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE nodes
+		SET deleted_at = NOW(), updated_at = NOW(), deletion_id = $3
+		WHERE project_id = $1 AND deleted_at IS NULL AND NOT (id = ANY($2::uuid[]))
+	`, projectID, keepIDs, uuid.New())
+	if err != nil {
+		return fmt.Errorf("failed to soft delete nodes: %w", err)
+	}
+
+	for _, n := range nodes {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO nodes (id, project_id, content, question, status, due_date, weight, created_at)
+			VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'todo'), $6, $7, $8)
+			ON CONFLICT (id) DO UPDATE
+			SET content = EXCLUDED.content, question = EXCLUDED.question,
+			    status = EXCLUDED.status, due_date = EXCLUDED.due_date, weight = EXCLUDED.weight,
+			    version = nodes.version + 1, deleted_at = NULL, deletion_id = NULL, updated_at = NOW()
+		`, n.ID, projectID, n.Content, n.Question, string(n.Status), n.DueDate, n.Weight, n.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to restore node: %w", err)
+		}
+	}
+
+	for _, e := range edges {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO edges (id, project_id, parent_node_id, child_node_id, relation, relation_label, order_index, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (child_node_id) DO UPDATE
+			SET parent_node_id = EXCLUDED.parent_node_id, relation = EXCLUDED.relation,
+			    relation_label = EXCLUDED.relation_label, order_index = EXCLUDED.order_index,
+			    updated_at = NOW()
+		`, e.ID, projectID, e.ParentNodeID, e.ChildNodeID, e.Relation, e.RelationLabel, e.OrderIndex, e.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to restore edge: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// tagRepository はタグリポジトリのPostgreSQL実装です
+type tagRepository struct {
+	db repository.DBInterface
+}
+
+// NewTagRepository は新しいタグリポジトリを作成します
+func NewTagRepository(db repository.DBInterface) repository.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(ctx context.Context, projectID uuid.UUID, req model.CreateTagRequest) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO tags (project_id, name, color)
+		VALUES ($1, $2, $3)
+		RETURNING id, project_id, name, color, created_at, updated_at
+	`, projectID, req.Name, req.Color).Scan(
+		&tag.ID, &tag.ProjectID, &tag.Name, &tag.Color, &tag.CreatedAt, &tag.UpdatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag: %w", err)
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Tag, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, name, color, created_at, updated_at
+		FROM tags
+		WHERE project_id = $1
+		ORDER BY lower(name)
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []model.Tag
+	for rows.Next() {
+		var t model.Tag
+		if err := rows.Scan(&t.ID, &t.ProjectID, &t.Name, &t.Color, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, t)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, tagID uuid.UUID) (*model.Tag, error) {
+	var tag model.Tag
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, name, color, created_at, updated_at
+		FROM tags
+		WHERE id = $1
+	`, tagID).Scan(
+		&tag.ID, &tag.ProjectID, &tag.Name, &tag.Color, &tag.CreatedAt, &tag.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tagID uuid.UUID, req model.UpdateTagRequest) error {
+	query := "UPDATE tags SET updated_at = NOW()"
+	args := []interface{}{}
+	argIndex := 1
+
+	if req.Name != nil {
+		query += fmt.Sprintf(", name = $%d", argIndex)
+		args = append(args, *req.Name)
+		argIndex++
+	}
+	if req.Color != nil {
+		query += fmt.Sprintf(", color = $%d", argIndex)
+		args = append(args, *req.Color)
+		argIndex++
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d", argIndex)
+	args = append(args, tagID)
+
+	_, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update tag: %w", err)
+	}
+	return nil
+}
+
+// Delete はタグを削除します（ノードとの対応は外部キーにより連鎖削除されます）
+func (r *tagRepository) Delete(ctx context.Context, tagID uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM tags WHERE id = $1`, tagID)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	return nil
+}
+
+func (r *tagRepository) ListNodeTags(ctx context.Context, projectID uuid.UUID) ([]model.NodeTag, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT nt.node_id, nt.tag_id
+		FROM node_tags nt
+		INNER JOIN tags t ON t.id = nt.tag_id
+		WHERE nt.project_id = $1
+		ORDER BY lower(t.name)
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list node tags: %w", err)
+	}
+	defer rows.Close()
+
+	var nodeTags []model.NodeTag
+	for rows.Next() {
+		var nt model.NodeTag
+		if err := rows.Scan(&nt.NodeID, &nt.TagID); err != nil {
+			return nil, fmt.Errorf("failed to scan node tag: %w", err)
+		}
+		nodeTags = append(nodeTags, nt)
+	}
+	return nodeTags, nil
+}
+
+// SetNodeTags はノードのタグを tagIDs に置き換えます
+func (r *tagRepository) SetNodeTags(ctx context.Context, projectID, nodeID uuid.UUID, tagIDs []uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	ids := make([]string, 0, len(tagIDs))
+	for _, id := range tagIDs {
+		ids = append(ids, id.String())
+	}
+	_, err = tx.Exec(ctx, `
+		DELETE FROM node_tags WHERE node_id = $1 AND NOT (tag_id = ANY($2::uuid[]))
+	`, nodeID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to remove node tags: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO node_tags (project_id, node_id, tag_id)
+		SELECT $1, $2, unnest($3::uuid[])
+		ON CONFLICT (node_id, tag_id) DO NOTHING
+	`, projectID, nodeID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to add node tags: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
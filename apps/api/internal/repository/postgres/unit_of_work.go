@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// unitOfWork はユニットオブワークのPostgreSQL実装です
+type unitOfWork struct {
+	db repository.DBInterface
+}
+
+func NewUnitOfWork(db repository.DBInterface) repository.UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(txRepositories(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (u *unitOfWork) Read(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	tx, err := u.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 最初の文で指定すると、以降の読み込みはすべて同じスナップショットを見る
+	if _, err := tx.Exec(ctx, `SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`); err != nil {
+		return fmt.Errorf("failed to set transaction mode: %w", err)
+	}
+	if err := fn(txRepositories(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func txRepositories(tx repository.TxInterface) repository.Repositories {
+	db := &txDB{tx: tx}
+	return repository.Repositories{
+		Projects:   NewProjectRepository(db),
+		Members:    NewMemberRepository(db),
+		Nodes:      NewNodeRepository(db),
+		Edges:      NewEdgeRepository(db),
+		Snapshots:  NewSnapshotRepository(db),
+		Tags:       NewTagRepository(db),
+		Operations: NewOperationRepository(db),
+	}
+}
+
+// txDB はトランザクションをリポジトリから DBInterface として使うためのアダプタです
+// リポジトリ内で開始したトランザクションはセーブポイントになります
+type txDB struct {
+	tx repository.TxInterface
+}
+
+func (db *txDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return db.tx.QueryRow(ctx, sql, args...)
+}
+
+func (db *txDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return db.tx.Query(ctx, sql, args...)
+}
+
+func (db *txDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return db.tx.Exec(ctx, sql, args...)
+}
+
+func (db *txDB) Begin(ctx context.Context) (repository.TxInterface, error) {
+	return db.tx.Begin(ctx)
+}
+
+// Close は何もしません（接続の寿命は外側のトランザクションが管理します）
+func (db *txDB) Close() {}
@@ -90,3 +90,25 @@ func (r *userRepository) GetByID(ctx context.Context, userID uuid.UUID) (*model.
 	}
 	return &user, nil
 }
+
+// GetByEmail はメールアドレス（大文字小文字を区別しない）でユーザーを取得します
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	var user model.User
+	err := r.db.QueryRow(ctx, `
+		SELECT id, google_id, email, name, picture, created_at, updated_at
+		FROM users
+		WHERE lower(email) = lower($1)
+		ORDER BY created_at
+		LIMIT 1
+	`, email).Scan(
+		&user.ID, &user.GoogleID, &user.Email, &user.Name, &user.Picture,
+		&user.CreatedAt, &user.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+	return &user, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/mokuhyo-driven-test/api/internal/model"
@@ -13,6 +14,7 @@ type UserRepository interface {
 	Create(ctx context.Context, googleID, email, name string, picture *string) (*model.User, error)
 	Update(ctx context.Context, userID uuid.UUID, email, name string, picture *string) (*model.User, error)
 	GetByID(ctx context.Context, userID uuid.UUID) (*model.User, error)
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
 }
 
 // ProjectRepository はプロジェクトリポジトリのインターフェースです
@@ -21,18 +23,75 @@ type ProjectRepository interface {
 	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Project, error)
 	GetByID(ctx context.Context, projectID uuid.UUID) (*model.Project, error)
 	Update(ctx context.Context, projectID uuid.UUID, req model.UpdateProjectRequest) error
-	CheckOwnership(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+	GetRevisionForUpdate(ctx context.Context, projectID uuid.UUID) (*int64, error)
 	UpdateUpdatedAt(ctx context.Context, projectID uuid.UUID) error
+	CreateWithTree(ctx context.Context, userID uuid.UUID, req model.CreateProjectRequest, nodes []model.Node, edges []model.Edge) (*model.Project, error)
+}
+
+// MemberRepository はプロジェクトメンバーリポジトリのインターフェースです
+type MemberRepository interface {
+	Add(ctx context.Context, projectID, userID uuid.UUID, role model.ProjectRole, invitedBy *uuid.UUID) error
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.ProjectMember, error)
+	GetRole(ctx context.Context, projectID, userID uuid.UUID) (*model.ProjectRole, error)
+	UpdateRole(ctx context.Context, projectID, userID uuid.UUID, role model.ProjectRole) error
+	Remove(ctx context.Context, projectID, userID uuid.UUID) error
+}
+
+// ShareLinkRepository は共有リンクリポジトリのインターフェースです
+type ShareLinkRepository interface {
+	Create(ctx context.Context, projectID, createdBy uuid.UUID, tokenHash string, label *string, expiresAt *time.Time) (*model.ShareLink, error)
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.ShareLink, error)
+	GetActiveByTokenHash(ctx context.Context, tokenHash string) (*model.ShareLink, error)
+	Revoke(ctx context.Context, projectID, shareLinkID uuid.UUID) (bool, error)
+}
+
+// CalendarFeedRepository はカレンダーフィードのトークンリポジトリのインターフェースです
+type CalendarFeedRepository interface {
+	Upsert(ctx context.Context, userID uuid.UUID, tokenHash string) error
+	GetUserIDByTokenHash(ctx context.Context, tokenHash string) (*uuid.UUID, error)
+	Delete(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// AuditRepository は監査ログリポジトリのインターフェースです
+type AuditRepository interface {
+	Create(ctx context.Context, event model.AuditEvent) error
+	ListByUserID(ctx context.Context, userID uuid.UUID, before *int64, limit int) ([]model.AuditEvent, error)
+}
+
+// TemplateRepository はユーザーが保存したテンプレートのリポジトリのインターフェースです
+type TemplateRepository interface {
+	Create(ctx context.Context, userID uuid.UUID, title string, description *string, nodes []model.TemplateNode) (*model.Template, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Template, error)
+	GetByID(ctx context.Context, templateID uuid.UUID) (*model.Template, error)
+	Delete(ctx context.Context, userID, templateID uuid.UUID) (bool, error)
 }
 
 // NodeRepository はノードリポジトリのインターフェースです
 type NodeRepository interface {
 	Create(ctx context.Context, projectID uuid.UUID, content string, question *string) (*model.Node, error)
 	GetByID(ctx context.Context, nodeID uuid.UUID) (*model.Node, error)
+	GetByIDForUpdate(ctx context.Context, nodeID uuid.UUID) (*model.Node, error)
 	Update(ctx context.Context, nodeID uuid.UUID, content string) error
+	UpdateQuestion(ctx context.Context, nodeID uuid.UUID, question *string) error
+	SetQuestionStatus(ctx context.Context, nodeID uuid.UUID, status *model.QuestionStatus) error
+	ListQuestionPendingIDs(ctx context.Context, projectID uuid.UUID) ([]uuid.UUID, error)
+	ListDueByUserID(ctx context.Context, userID uuid.UUID) ([]model.AgendaItem, error)
+	UpdateStatus(ctx context.Context, nodeID uuid.UUID, status model.NodeStatus, dueDate *model.Date, weight *float64) error
 	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Node, error)
 	SoftDeleteWithDescendants(ctx context.Context, projectID, nodeID uuid.UUID) error
 	GetMaxOrderIndex(ctx context.Context, projectID uuid.UUID, parentNodeID *uuid.UUID) (int, error)
+	GetByIDIncludingDeleted(ctx context.Context, nodeID uuid.UUID) (*model.Node, error)
+	ListDeleted(ctx context.Context, projectID uuid.UUID) ([]model.TrashEntry, error)
+	RestoreWithDescendants(ctx context.Context, projectID, nodeID uuid.UUID) error
+	PurgeDeleted(ctx context.Context, projectID *uuid.UUID, before time.Time) (int64, error)
+	CreateSubtree(ctx context.Context, projectID uuid.UUID, nodes []model.Node, edges []model.Edge) ([]model.Node, []model.Edge, error)
+	UpdateText(ctx context.Context, nodeID uuid.UUID, content string, question *string) error
+}
+
+// NodeRevisionRepository はノードの編集履歴リポジトリのインターフェースです（版はトリガーで保存されます）
+type NodeRevisionRepository interface {
+	ListByNodeID(ctx context.Context, nodeID uuid.UUID) ([]model.NodeRevision, error)
+	GetByID(ctx context.Context, revisionID uuid.UUID) (*model.NodeRevision, error)
 }
 
 // EdgeRepository はエッジリポジトリのインターフェースです
@@ -42,6 +101,44 @@ type EdgeRepository interface {
 	GetByID(ctx context.Context, edgeID uuid.UUID) (*model.Edge, error)
 	Update(ctx context.Context, edgeID uuid.UUID, relation *string, relationLabel *string) error
 	Reorder(ctx context.Context, projectID uuid.UUID, parentNodeID *uuid.UUID, orderedChildNodeIDs []uuid.UUID) error
+	GetByChildNodeID(ctx context.Context, childNodeID uuid.UUID) (*model.Edge, error)
+	SetRelation(ctx context.Context, edgeID uuid.UUID, relation model.RelationType, relationLabel *string) error
+	Move(ctx context.Context, projectID, childNodeID uuid.UUID, newParentNodeID *uuid.UUID, orderIndex int) error
+}
+
+// TagRepository はタグリポジトリのインターフェースです
+type TagRepository interface {
+	Create(ctx context.Context, projectID uuid.UUID, req model.CreateTagRequest) (*model.Tag, error)
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Tag, error)
+	GetByID(ctx context.Context, tagID uuid.UUID) (*model.Tag, error)
+	Update(ctx context.Context, tagID uuid.UUID, req model.UpdateTagRequest) error
+	Delete(ctx context.Context, tagID uuid.UUID) error
+	ListNodeTags(ctx context.Context, projectID uuid.UUID) ([]model.NodeTag, error)
+	SetNodeTags(ctx context.Context, projectID, nodeID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+// OperationRepository は取り消し・やり直し用の操作履歴リポジトリのインターフェースです
+type OperationRepository interface {
+	Append(ctx context.Context, op model.Operation) error
+	DiscardUndone(ctx context.Context, projectID, userID uuid.UUID) error
+	GetLastDone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error)
+	GetFirstUndone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error)
+	SetState(ctx context.Context, operationID uuid.UUID, state model.OperationState) error
+}
+
+// SnapshotRepository はスナップショットリポジトリのインターフェースです
+type SnapshotRepository interface {
+	Create(ctx context.Context, projectID uuid.UUID, payload model.TreeResponse) (*model.Snapshot, error)
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Snapshot, error)
+	GetByVersion(ctx context.Context, projectID uuid.UUID, version int) (*model.Snapshot, error)
+	RestoreTree(ctx context.Context, projectID uuid.UUID, nodes []model.Node, edges []model.Edge) error
+}
+
+// SearchRepository は検索リポジトリのインターフェースです
+// patterns は ILIKE 用にエスケープ済みのパターンで、すべてに一致したものだけを返します
+type SearchRepository interface {
+	SearchProjects(ctx context.Context, userID uuid.UUID, query string, patterns []string, limit int) ([]model.SearchCandidate, error)
+	SearchNodes(ctx context.Context, userID uuid.UUID, query string, patterns []string, limit int) ([]model.SearchCandidate, error)
 }
 
 // SettingsRepository は設定リポジトリのインターフェースです
@@ -91,6 +91,15 @@ func (tx *SupabaseTx) Exec(ctx context.Context, sql string, args ...interface{})
 	return tx.tx.Exec(ctx, sql, args...)
 }
 
+// Begin はセーブポイントとして入れ子のトランザクションを開始します
+func (tx *SupabaseTx) Begin(ctx context.Context) (repository.TxInterface, error) {
+	nested, err := tx.tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SupabaseTx{tx: nested}, nil
+}
+
 // Commit はトランザクションをコミットします
 func (tx *SupabaseTx) Commit(ctx context.Context) error {
 	return tx.tx.Commit(ctx)
@@ -109,6 +109,18 @@ func (r *edgeRepository) Update(ctx context.Context, edgeID uuid.UUID, relation
 	return nil
 }
 
+// SetRelation は関係とラベルをそのまま置き換えます（relationLabel が nil の場合はラベルを消します）
+func (r *edgeRepository) SetRelation(ctx context.Context, edgeID uuid.UUID, relation model.RelationType, relationLabel *string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE edges SET relation = $1, relation_label = $2, updated_at = NOW()
+		WHERE id = $3
+	`, relation, relationLabel, edgeID)
+	if err != nil {
+		return fmt.Errorf("failed to set edge relation: %w", err)
+	}
+	return nil
+}
+
 func (r *edgeRepository) Reorder(ctx context.Context, projectID uuid.UUID, parentNodeID *uuid.UUID, orderedChildNodeIDs []uuid.UUID) error {
 	tx, err := r.db.Begin(ctx)
 	if err != nil {
@@ -142,3 +154,137 @@ func (r *edgeRepository) Reorder(ctx context.Context, projectID uuid.UUID, paren
 	}
 	return nil
 }
+
+func (r *edgeRepository) GetByChildNodeID(ctx context.Context, childNodeID uuid.UUID) (*model.Edge, error) {
+	var edge model.Edge
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, parent_node_id, child_node_id, relation, relation_label, order_index, created_at, updated_at
+		FROM edges
+		WHERE child_node_id = $1
+	`, childNodeID).Scan(
+		&edge.ID, &edge.ProjectID, &edge.ParentNodeID, &edge.ChildNodeID,
+		&edge.Relation, &edge.RelationLabel, &edge.OrderIndex,
+		&edge.CreatedAt, &edge.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get edge by child node: %w", err)
+	}
+	return &edge, nil
+}
+
+// Move はノードの親を付け替え、移動元と移動先の兄弟の order_index を振り直します
+func (r *edgeRepository) Move(ctx context.Context, projectID, childNodeID uuid.UUID, newParentNodeID *uuid.UUID, orderIndex int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var oldParentNodeID *uuid.UUID
+	err = tx.QueryRow(ctx, `
+		SELECT parent_node_id FROM edges
+		WHERE project_id = $1 AND child_node_id = $2
+		FOR UPDATE
+	`, projectID, childNodeID).Scan(&oldParentNodeID)
+	if err != nil {
+		return fmt.Errorf("failed to get edge: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE edges SET parent_node_id = $1, updated_at = NOW()
+		WHERE project_id = $2 AND child_node_id = $3
+	`, newParentNodeID, projectID, childNodeID)
+	if err != nil {
+		return fmt.Errorf("failed to move edge: %w", err)
+	}
+
+	// 移動先の兄弟（自身を除く）を取得して指定位置に挿入
+	siblings, err := listChildNodeIDs(ctx, tx, projectID, newParentNodeID, childNodeID)
+	if err != nil {
+		return err
+	}
+	if orderIndex < 0 || orderIndex > len(siblings) {
+		orderIndex = len(siblings)
+	}
+	ordered := make([]uuid.UUID, 0, len(siblings)+1)
+	ordered = append(ordered, siblings[:orderIndex]...)
+	ordered = append(ordered, childNodeID)
+	ordered = append(ordered, siblings[orderIndex:]...)
+	if err := renumberChildren(ctx, tx, projectID, ordered); err != nil {
+		return err
+	}
+
+	// 移動元の兄弟を詰め直す
+	if !sameParent(oldParentNodeID, newParentNodeID) {
+		oldSiblings, err := listChildNodeIDs(ctx, tx, projectID, oldParentNodeID, childNodeID)
+		if err != nil {
+			return err
+		}
+		if err := renumberChildren(ctx, tx, projectID, oldSiblings); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// listChildNodeIDs は親ノード直下の削除されていない子ノードIDを order_index 順に返します
+func listChildNodeIDs(ctx context.Context, tx repository.TxInterface, projectID uuid.UUID, parentNodeID *uuid.UUID, excludeNodeID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT e.child_node_id
+		FROM edges e
+		INNER JOIN nodes n ON e.child_node_id = n.id
+		WHERE e.project_id = $1 AND n.deleted_at IS NULL AND e.child_node_id <> $2
+	`
+	args := []interface{}{projectID, excludeNodeID}
+	if parentNodeID == nil {
+		query += " AND e.parent_node_id IS NULL"
+	} else {
+		query += " AND e.parent_node_id = $3"
+		args = append(args, *parentNodeID)
+	}
+	query += " ORDER BY e.order_index, e.created_at"
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sibling edges: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan sibling edge: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// renumberChildren は渡された順に order_index を 0 から振り直します
+func renumberChildren(ctx context.Context, tx repository.TxInterface, projectID uuid.UUID, orderedChildNodeIDs []uuid.UUID) error {
+	for i, childID := range orderedChildNodeIDs {
+		_, err := tx.Exec(ctx, `
+			UPDATE edges SET order_index = $1, updated_at = NOW()
+			WHERE project_id = $2 AND child_node_id = $3
+		`, i, projectID, childID)
+		if err != nil {
+			return fmt.Errorf("failed to renumber edge: %w", err)
+		}
+	}
+	return nil
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -3,6 +3,7 @@ package supabase
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -25,9 +26,9 @@ func (r *nodeRepository) Create(ctx context.Context, projectID uuid.UUID, conten
 	err := r.db.QueryRow(ctx, `
 		INSERT INTO nodes (project_id, content, question)
 		VALUES ($1, $2, $3)
-		RETURNING id, project_id, content, question, created_at, updated_at, deleted_at
+		RETURNING id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
 	`, projectID, content, question).Scan(
-		&node.ID, &node.ProjectID, &node.Content, &node.Question,
+		&node.ID, &node.ProjectID, &node.Content, &node.Question, &node.QuestionStatus, &node.Status, &node.DueDate, &node.Weight, &node.Version,
 		&node.CreatedAt, &node.UpdatedAt, &node.DeletedAt,
 	)
 	if err != nil {
@@ -39,11 +40,32 @@ func (r *nodeRepository) Create(ctx context.Context, projectID uuid.UUID, conten
 func (r *nodeRepository) GetByID(ctx context.Context, nodeID uuid.UUID) (*model.Node, error) {
 	var node model.Node
 	err := r.db.QueryRow(ctx, `
-		SELECT id, project_id, content, question, created_at, updated_at, deleted_at
+		SELECT id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
 		FROM nodes
 		WHERE id = $1 AND deleted_at IS NULL
 	`, nodeID).Scan(
-		&node.ID, &node.ProjectID, &node.Content, &node.Question,
+		&node.ID, &node.ProjectID, &node.Content, &node.Question, &node.QuestionStatus, &node.Status, &node.DueDate, &node.Weight, &node.Version,
+		&node.CreatedAt, &node.UpdatedAt, &node.DeletedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return &node, nil
+}
+
+// GetByIDForUpdate はノードを取得し、トランザクションの終了まで行をロックします
+func (r *nodeRepository) GetByIDForUpdate(ctx context.Context, nodeID uuid.UUID) (*model.Node, error) {
+	var node model.Node
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, nodeID).Scan(
+		&node.ID, &node.ProjectID, &node.Content, &node.Question, &node.QuestionStatus, &node.Status, &node.DueDate, &node.Weight, &node.Version,
 		&node.CreatedAt, &node.UpdatedAt, &node.DeletedAt,
 	)
 	if err == pgx.ErrNoRows {
@@ -57,7 +79,7 @@ func (r *nodeRepository) GetByID(ctx context.Context, nodeID uuid.UUID) (*model.
 
 func (r *nodeRepository) Update(ctx context.Context, nodeID uuid.UUID, content string) error {
 	_, err := r.db.Exec(ctx, `
-		UPDATE nodes SET content = $1, updated_at = NOW()
+		UPDATE nodes SET content = $1, version = version + 1, updated_at = NOW()
 		WHERE id = $2 AND deleted_at IS NULL
 	`, content, nodeID)
 	if err != nil {
@@ -66,9 +88,78 @@ func (r *nodeRepository) Update(ctx context.Context, nodeID uuid.UUID, content s
 	return nil
 }
 
+// UpdateText はノードの内容と質問を1回の更新で置き換えます（編集履歴の版も1つだけ増えます）
+func (r *nodeRepository) UpdateText(ctx context.Context, nodeID uuid.UUID, content string, question *string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE nodes SET content = $1, question = $2, version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND deleted_at IS NULL
+	`, content, question, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to update node text: %w", err)
+	}
+	return nil
+}
+
+// UpdateQuestion はノードの質問だけを更新し、生成中の状態を解除します（非同期の質問生成の結果を保存するために使います）
+func (r *nodeRepository) UpdateQuestion(ctx context.Context, nodeID uuid.UUID, question *string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE nodes SET question = $1, question_status = NULL, version = version + 1, updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL
+	`, question, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to update node question: %w", err)
+	}
+	return nil
+}
+
+// SetQuestionStatus は質問の生成状況を設定します（nil で解除）。バージョンは変えません
+func (r *nodeRepository) SetQuestionStatus(ctx context.Context, nodeID uuid.UUID, status *model.QuestionStatus) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE nodes SET question_status = $1 WHERE id = $2
+	`, status, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to set question status: %w", err)
+	}
+	return nil
+}
+
+// ListQuestionPendingIDs は質問を生成中のノードIDを返します
+func (r *nodeRepository) ListQuestionPendingIDs(ctx context.Context, projectID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM nodes
+		WHERE project_id = $1 AND question_status = 'pending' AND deleted_at IS NULL
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list question pending nodes: %w", err)
+	}
+	defer rows.Close()
+
+	nodeIDs := []uuid.UUID{}
+	for rows.Next() {
+		var nodeID uuid.UUID
+		if err := rows.Scan(&nodeID); err != nil {
+			return nil, fmt.Errorf("failed to scan node id: %w", err)
+		}
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	return nodeIDs, nil
+}
+
+// UpdateStatus はノードの進捗（状態・期日・重み）を更新します
+func (r *nodeRepository) UpdateStatus(ctx context.Context, nodeID uuid.UUID, status model.NodeStatus, dueDate *model.Date, weight *float64) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE nodes SET status = $1, due_date = $2, weight = $3, version = version + 1, updated_at = NOW()
+		WHERE id = $4 AND deleted_at IS NULL
+	`, status, dueDate, weight, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to update node status: %w", err)
+	}
+	return nil
+}
+
 func (r *nodeRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Node, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, project_id, content, question, created_at, updated_at, deleted_at
+		SELECT id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
 		FROM nodes
 		WHERE project_id = $1 AND deleted_at IS NULL
 	`, projectID)
@@ -80,7 +171,7 @@ func (r *nodeRepository) ListByProjectID(ctx context.Context, projectID uuid.UUI
 	var nodes []model.Node
 	for rows.Next() {
 		var n model.Node
-		if err := rows.Scan(&n.ID, &n.ProjectID, &n.Content, &n.Question,
+		if err := rows.Scan(&n.ID, &n.ProjectID, &n.Content, &n.Question, &n.QuestionStatus, &n.Status, &n.DueDate, &n.Weight, &n.Version,
 			&n.CreatedAt, &n.UpdatedAt, &n.DeletedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan node: %w", err)
 		}
@@ -89,7 +180,65 @@ func (r *nodeRepository) ListByProjectID(ctx context.Context, projectID uuid.UUI
 	return nodes, nil
 }
 
+// ListDueByUserID は userID がメンバーのアーカイブされていない全プロジェクトから、期日が設定された未完了
+// （done / dropped 以外）のノードを、根から親までの祖先の内容付きで期日順に1回のクエリで返します
+func (r *nodeRepository) ListDueByUserID(ctx context.Context, userID uuid.UUID) ([]model.AgendaItem, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH RECURSIVE due AS (
+			SELECT n.id, n.project_id, n.content, n.question, COALESCE(n.question_status, '') AS question_status,
+			       n.status, n.due_date, n.weight, n.version, n.created_at, n.updated_at, n.deleted_at,
+			       p.title AS project_title
+			FROM project_members m
+			INNER JOIN projects p ON p.id = m.project_id AND p.archived_at IS NULL
+			INNER JOIN nodes n ON n.project_id = p.id
+			WHERE m.user_id = $1
+			  AND n.due_date IS NOT NULL
+			  AND n.deleted_at IS NULL
+			  AND n.status NOT IN ('done', 'dropped')
+		), ancestors AS (
+			SELECT d.id AS node_id, e.parent_node_id AS ancestor_id, 1 AS depth
+			FROM due d
+			INNER JOIN edges e ON e.child_node_id = d.id
+			WHERE e.parent_node_id IS NOT NULL
+			UNION ALL
+			-- depth は壊れたデータで親子が循環していても止まるための上限
+			SELECT a.node_id, e.parent_node_id, a.depth + 1
+			FROM ancestors a
+			INNER JOIN edges e ON e.child_node_id = a.ancestor_id
+			WHERE e.parent_node_id IS NOT NULL AND a.depth < 1000
+		)
+		SELECT d.id, d.project_id, d.content, d.question, d.question_status, d.status, d.due_date, d.weight, d.version,
+		       d.created_at, d.updated_at, d.deleted_at, d.project_title,
+		       COALESCE((
+			       SELECT array_agg(n.content ORDER BY a.depth DESC)
+			       FROM ancestors a
+			       INNER JOIN nodes n ON n.id = a.ancestor_id
+			       WHERE a.node_id = d.id
+		       ), '{}')
+		FROM due d
+		ORDER BY d.due_date, d.project_title
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var items []model.AgendaItem
+	for rows.Next() {
+		var item model.AgendaItem
+		n := &item.Node
+		if err := rows.Scan(&n.ID, &n.ProjectID, &n.Content, &n.Question, &n.QuestionStatus, &n.Status, &n.DueDate, &n.Weight, &n.Version,
+			&n.CreatedAt, &n.UpdatedAt, &n.DeletedAt, &item.ProjectTitle, &item.Path); err != nil {
+			return nil, fmt.Errorf("failed to scan due node: %w", err)
+		}
+		item.ProjectID = n.ProjectID
+		items = append(items, item)
+	}
+	return items, nil
+}
+
 func (r *nodeRepository) SoftDeleteWithDescendants(ctx context.Context, projectID, nodeID uuid.UUID) error {
+	// 同じ削除操作で消えたノードを deletion_id でまとめ、後から一括で復元できるようにする
 	_, err := r.db.Exec(ctx, `
 		WITH RECURSIVE subtree AS (
 			SELECT e.child_node_id AS id
@@ -102,10 +251,11 @@ func (r *nodeRepository) SoftDeleteWithDescendants(ctx context.Context, projectI
 			WHERE e.project_id = $1
 		)
 		UPDATE nodes
-		SET deleted_at = NOW(), updated_at = NOW()
+		SET deleted_at = NOW(), updated_at = NOW(), deletion_id = $3
 		WHERE project_id = $1
+		  AND deleted_at IS NULL
 		  AND id IN (SELECT id FROM subtree)
-	`, projectID, nodeID)
+	`, projectID, nodeID, uuid.New())
 	if err != nil {
 		return fmt.Errorf("failed to soft delete with descendants: %w", err)
 	}
@@ -134,3 +284,184 @@ func (r *nodeRepository) GetMaxOrderIndex(ctx context.Context, projectID uuid.UU
 	}
 	return maxOrder, nil
 }
+
+func (r *nodeRepository) GetByIDIncludingDeleted(ctx context.Context, nodeID uuid.UUID) (*model.Node, error) {
+	var node model.Node
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
+		FROM nodes
+		WHERE id = $1
+	`, nodeID).Scan(
+		&node.ID, &node.ProjectID, &node.Content, &node.Question, &node.QuestionStatus, &node.Status, &node.DueDate, &node.Weight, &node.Version,
+		&node.CreatedAt, &node.UpdatedAt, &node.DeletedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node: %w", err)
+	}
+	return &node, nil
+}
+
+// ListDeleted は削除操作ごとに、その操作で削除された部分木の根ノードと件数を返します
+func (r *nodeRepository) ListDeleted(ctx context.Context, projectID uuid.UUID) ([]model.TrashEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT n.deletion_id, n.deleted_at,
+		       n.id, n.project_id, n.content, n.question, n.status, n.due_date, n.weight, n.version, n.created_at, n.updated_at, n.deleted_at,
+		       (SELECT COUNT(*) FROM nodes m WHERE m.deletion_id = n.deletion_id)
+		FROM nodes n
+		INNER JOIN edges e ON e.child_node_id = n.id
+		LEFT JOIN nodes p ON p.id = e.parent_node_id
+		WHERE n.project_id = $1
+		  AND n.deleted_at IS NOT NULL
+		  AND n.deletion_id IS NOT NULL
+		  AND (p.id IS NULL OR p.deletion_id IS DISTINCT FROM n.deletion_id)
+		ORDER BY n.deleted_at DESC
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []model.TrashEntry
+	for rows.Next() {
+		var t model.TrashEntry
+		if err := rows.Scan(&t.DeletionID, &t.DeletedAt,
+			&t.Node.ID, &t.Node.ProjectID, &t.Node.Content, &t.Node.Question, &t.Node.Status, &t.Node.DueDate, &t.Node.Weight, &t.Node.Version,
+			&t.Node.CreatedAt, &t.Node.UpdatedAt, &t.Node.DeletedAt,
+			&t.NodeCount); err != nil {
+			return nil, fmt.Errorf("failed to scan deleted node: %w", err)
+		}
+		entries = append(entries, t)
+	}
+	return entries, nil
+}
+
+// RestoreWithDescendants はノードと、同じ削除操作で削除された子孫のみを復元し、兄弟の末尾に戻します
+func (r *nodeRepository) RestoreWithDescendants(ctx context.Context, projectID, nodeID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		WITH RECURSIVE subtree AS (
+			SELECT n.id, n.deletion_id
+			FROM nodes n
+			WHERE n.project_id = $1 AND n.id = $2 AND n.deleted_at IS NOT NULL
+			UNION ALL
+			SELECT n.id, n.deletion_id
+			FROM edges e
+			JOIN subtree s ON e.parent_node_id = s.id
+			JOIN nodes n ON n.id = e.child_node_id
+			WHERE e.project_id = $1 AND n.deletion_id = s.deletion_id
+		)
+		UPDATE nodes
+		SET deleted_at = NULL, deletion_id = NULL, updated_at = NOW()
+		WHERE project_id = $1
+		  AND id IN (SELECT id FROM subtree)
+	`, projectID, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to restore with descendants: %w", err)
+	}
+
+	// 削除中に兄弟が移動・並び替えされていても order_index が重ならないよう、
+	// 親の子を 0 から振り直し、復元したノードを末尾に置く
+	_, err = tx.Exec(ctx, `
+		WITH parent AS (
+			SELECT parent_node_id FROM edges
+			WHERE project_id = $1 AND child_node_id = $2
+		), ordered AS (
+			SELECT s.id, ROW_NUMBER() OVER (ORDER BY s.child_node_id = $2, s.order_index, s.created_at) - 1 AS order_index
+			FROM edges s
+			INNER JOIN nodes n ON s.child_node_id = n.id
+			CROSS JOIN parent p
+			WHERE s.project_id = $1
+			  AND s.parent_node_id IS NOT DISTINCT FROM p.parent_node_id
+			  AND n.deleted_at IS NULL
+		)
+		UPDATE edges e
+		SET order_index = o.order_index, updated_at = NOW()
+		FROM ordered o
+		WHERE e.id = o.id AND (e.order_index <> o.order_index OR e.child_node_id = $2)
+	`, projectID, nodeID)
+	if err != nil {
+		return fmt.Errorf("failed to reorder restored edge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// PurgeDeleted は before より前に論理削除されたノードを物理削除します
+// projectID が nil の場合は全プロジェクトが対象です（エッジは外部キーにより連鎖削除されます）
+func (r *nodeRepository) PurgeDeleted(ctx context.Context, projectID *uuid.UUID, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM nodes
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`
+	args := []interface{}{before}
+	if projectID != nil {
+		query += " AND project_id = $2"
+		args = append(args, *projectID)
+	}
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted nodes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// CreateSubtree は採番済みのノードとエッジを1つのトランザクションで作成し、作成した行を返します
+func (r *nodeRepository) CreateSubtree(ctx context.Context, projectID uuid.UUID, nodes []model.Node, edges []model.Edge) ([]model.Node, []model.Edge, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	created := make([]model.Node, 0, len(nodes))
+	for _, n := range nodes {
+		var node model.Node
+		err := tx.QueryRow(ctx, `
+			INSERT INTO nodes (id, project_id, content, question, status, due_date, weight)
+			VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'todo'), $6, $7)
+			RETURNING id, project_id, content, question, COALESCE(question_status, ''), status, due_date, weight, version, created_at, updated_at, deleted_at
+		`, n.ID, projectID, n.Content, n.Question, string(n.Status), n.DueDate, n.Weight).Scan(
+			&node.ID, &node.ProjectID, &node.Content, &node.Question, &node.QuestionStatus, &node.Status, &node.DueDate, &node.Weight, &node.Version,
+			&node.CreatedAt, &node.UpdatedAt, &node.DeletedAt,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create node: %w", err)
+		}
+		created = append(created, node)
+	}
+
+	createdEdges := make([]model.Edge, 0, len(edges))
+	for _, e := range edges {
+		var edge model.Edge
+		err := tx.QueryRow(ctx, `
+			INSERT INTO edges (project_id, parent_node_id, child_node_id, relation, relation_label, order_index)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, project_id, parent_node_id, child_node_id, relation, relation_label, order_index, created_at, updated_at
+		`, projectID, e.ParentNodeID, e.ChildNodeID, e.Relation, e.RelationLabel, e.OrderIndex).Scan(
+			&edge.ID, &edge.ProjectID, &edge.ParentNodeID, &edge.ChildNodeID,
+			&edge.Relation, &edge.RelationLabel, &edge.OrderIndex,
+			&edge.CreatedAt, &edge.UpdatedAt,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create edge: %w", err)
+		}
+		createdEdges = append(createdEdges, edge)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return created, createdEdges, nil
+}
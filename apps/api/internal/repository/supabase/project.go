@@ -23,12 +23,19 @@ func NewProjectRepository(db repository.DBInterface) repository.ProjectRepositor
 
 func (r *projectRepository) Create(ctx context.Context, userID uuid.UUID, req model.CreateProjectRequest) (*model.Project, error) {
 	var project model.Project
+	// 作成者をオーナーとしてメンバーに登録する
 	err := r.db.QueryRow(ctx, `
-		INSERT INTO projects (user_id, title, description)
-		VALUES ($1, $2, $3)
-		RETURNING id, user_id, title, description, created_at, updated_at, archived_at
+		WITH p AS (
+			INSERT INTO projects (user_id, title, description)
+			VALUES ($1, $2, $3)
+			RETURNING id, user_id, title, description, revision, created_at, updated_at, archived_at
+		), m AS (
+			INSERT INTO project_members (project_id, user_id, role)
+			SELECT id, user_id, 'owner' FROM p
+		)
+		SELECT id, user_id, title, description, revision, created_at, updated_at, archived_at FROM p
 	`, userID, req.Title, req.Description).Scan(
-		&project.ID, &project.UserID, &project.Title, &project.Description,
+		&project.ID, &project.UserID, &project.Title, &project.Description, &project.Revision,
 		&project.CreatedAt, &project.UpdatedAt, &project.ArchivedAt,
 	)
 	if err != nil {
@@ -39,10 +46,11 @@ func (r *projectRepository) Create(ctx context.Context, userID uuid.UUID, req mo
 
 func (r *projectRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Project, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, user_id, title, description, created_at, updated_at, archived_at
-		FROM projects
-		WHERE user_id = $1 AND archived_at IS NULL
-		ORDER BY updated_at DESC
+		SELECT p.id, p.user_id, p.title, p.description, p.revision, p.created_at, p.updated_at, p.archived_at, m.role
+		FROM projects p
+		INNER JOIN project_members m ON m.project_id = p.id
+		WHERE m.user_id = $1 AND p.archived_at IS NULL
+		ORDER BY p.updated_at DESC
 	`, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list projects: %w", err)
@@ -52,8 +60,8 @@ func (r *projectRepository) ListByUserID(ctx context.Context, userID uuid.UUID)
 	var projects []model.Project
 	for rows.Next() {
 		var p model.Project
-		if err := rows.Scan(&p.ID, &p.UserID, &p.Title, &p.Description,
-			&p.CreatedAt, &p.UpdatedAt, &p.ArchivedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Title, &p.Description, &p.Revision,
+			&p.CreatedAt, &p.UpdatedAt, &p.ArchivedAt, &p.Role); err != nil {
 			return nil, fmt.Errorf("failed to scan project: %w", err)
 		}
 		projects = append(projects, p)
@@ -64,11 +72,11 @@ func (r *projectRepository) ListByUserID(ctx context.Context, userID uuid.UUID)
 func (r *projectRepository) GetByID(ctx context.Context, projectID uuid.UUID) (*model.Project, error) {
 	var project model.Project
 	err := r.db.QueryRow(ctx, `
-		SELECT id, user_id, title, description, created_at, updated_at, archived_at
+		SELECT id, user_id, title, description, revision, created_at, updated_at, archived_at
 		FROM projects
 		WHERE id = $1
 	`, projectID).Scan(
-		&project.ID, &project.UserID, &project.Title, &project.Description,
+		&project.ID, &project.UserID, &project.Title, &project.Description, &project.Revision,
 		&project.CreatedAt, &project.UpdatedAt, &project.ArchivedAt,
 	)
 	if err == pgx.ErrNoRows {
@@ -81,7 +89,7 @@ func (r *projectRepository) GetByID(ctx context.Context, projectID uuid.UUID) (*
 }
 
 func (r *projectRepository) Update(ctx context.Context, projectID uuid.UUID, req model.UpdateProjectRequest) error {
-	query := "UPDATE projects SET updated_at = NOW()"
+	query := "UPDATE projects SET revision = revision + 1, updated_at = NOW()"
 	args := []interface{}{}
 	argIndex := 1
 
@@ -113,15 +121,19 @@ func (r *projectRepository) Update(ctx context.Context, projectID uuid.UUID, req
 	return nil
 }
 
-func (r *projectRepository) CheckOwnership(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
-	var count int
+// GetRevisionForUpdate はプロジェクトのリビジョンを取得し、トランザクションの終了まで行をロックします
+func (r *projectRepository) GetRevisionForUpdate(ctx context.Context, projectID uuid.UUID) (*int64, error) {
+	var revision int64
 	err := r.db.QueryRow(ctx, `
-		SELECT COUNT(*) FROM projects WHERE id = $1 AND user_id = $2
-	`, projectID, userID).Scan(&count)
+		SELECT revision FROM projects WHERE id = $1 FOR UPDATE
+	`, projectID).Scan(&revision)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return false, fmt.Errorf("failed to check ownership: %w", err)
+		return nil, fmt.Errorf("failed to get project revision: %w", err)
 	}
-	return count > 0, nil
+	return &revision, nil
 }
 
 func (r *projectRepository) UpdateUpdatedAt(ctx context.Context, projectID uuid.UUID) error {
@@ -133,3 +145,64 @@ func (r *projectRepository) UpdateUpdatedAt(ctx context.Context, projectID uuid.
 	}
 	return nil
 }
+
+// CreateWithTree はプロジェクトと、その配下のノード・エッジを1つのトランザクションで作成します
+// nodes と edges のIDは呼び出し側で採番済みである必要があります
+func (r *projectRepository) CreateWithTree(ctx context.Context, userID uuid.UUID, req model.CreateProjectRequest, nodes []model.Node, edges []model.Edge) (*model.Project, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var project model.Project
+	err = tx.QueryRow(ctx, `
+		INSERT INTO projects (user_id, title, description)
+		VALUES ($1, $2, $3)
+		RETURNING id, user_id, title, description, revision, created_at, updated_at, archived_at
+	`, userID, req.Title, req.Description).Scan(
+		&project.ID, &project.UserID, &project.Title, &project.Description, &project.Revision,
+		&project.CreatedAt, &project.UpdatedAt, &project.ArchivedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create project: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO project_members (project_id, user_id, role)
+		VALUES ($1, $2, 'owner')
+	`, project.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add project owner: %w", err)
+	}
+
+	for _, n := range nodes {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO nodes (id, project_id, content, question, status, due_date, weight)
+			VALUES ($1, $2, $3, $4, COALESCE(NULLIF($5, ''), 'todo'), $6, $7)
+		`, n.ID, project.ID, n.Content, n.Question, string(n.Status), n.DueDate, n.Weight)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create node: %w", err)
+		}
+	}
+
+	for _, e := range edges {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO edges (project_id, parent_node_id, child_node_id, relation, relation_label, order_index)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, project.ID, e.ParentNodeID, e.ChildNodeID, e.Relation, e.RelationLabel, e.OrderIndex)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create edge: %w", err)
+		}
+	}
+
+	// ノードとエッジの作成でトリガーにより進んだリビジョンを読み直す
+	if err := tx.QueryRow(ctx, `SELECT revision FROM projects WHERE id = $1`, project.ID).Scan(&project.Revision); err != nil {
+		return nil, fmt.Errorf("failed to get project revision: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return &project, nil
+}
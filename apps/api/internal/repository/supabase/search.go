@@ -0,0 +1,116 @@
+package supabase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// searchRepository は検索リポジトリのSupabase実装です
+// pg_trgm のインデックスで ILIKE による部分一致を高速化します（空白で区切らない日本語にも対応）
+type searchRepository struct {
+	db repository.DBInterface
+}
+
+// NewSearchRepository は新しい検索リポジトリを作成します
+func NewSearchRepository(db repository.DBInterface) repository.SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// SearchProjects はユーザーがメンバーのプロジェクトから、タイトルまたは説明が検索語に一致するものを返します
+func (r *searchRepository) SearchProjects(ctx context.Context, userID uuid.UUID, query string, patterns []string, limit int) ([]model.SearchCandidate, error) {
+	sql := `
+		SELECT p.id, p.title, p.description,
+		       GREATEST(word_similarity($2, p.title), word_similarity($2, COALESCE(p.description, ''))) AS score
+		FROM projects p
+		INNER JOIN project_members m ON m.project_id = p.id AND m.user_id = $1
+		WHERE p.archived_at IS NULL
+	`
+	args := []interface{}{userID, query, limit}
+	for _, pattern := range patterns {
+		args = append(args, pattern)
+		sql += fmt.Sprintf(" AND (p.title ILIKE $%d OR p.description ILIKE $%d)", len(args), len(args))
+	}
+	sql += " ORDER BY score DESC, p.updated_at DESC LIMIT $3"
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search projects: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []model.SearchCandidate
+	for rows.Next() {
+		var c model.SearchCandidate
+		if err := rows.Scan(&c.ProjectID, &c.ProjectTitle, &c.ProjectDescription, &c.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan project search result: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
+
+// SearchNodes はユーザーがメンバーのプロジェクトから、内容または質問が検索語に一致するノードを祖先のパス付きで返します
+func (r *searchRepository) SearchNodes(ctx context.Context, userID uuid.UUID, query string, patterns []string, limit int) ([]model.SearchCandidate, error) {
+	conditions := ""
+	args := []interface{}{userID, query, limit}
+	for _, pattern := range patterns {
+		args = append(args, pattern)
+		conditions += fmt.Sprintf(" AND (n.content ILIKE $%d OR n.question ILIKE $%d)", len(args), len(args))
+	}
+
+	sql := `
+		WITH RECURSIVE hits AS (
+			SELECT n.id, n.project_id, p.title AS project_title, n.content, n.question, n.updated_at,
+			       GREATEST(word_similarity($2, n.content), word_similarity($2, COALESCE(n.question, ''))) AS score
+			FROM nodes n
+			INNER JOIN projects p ON p.id = n.project_id
+			INNER JOIN project_members m ON m.project_id = n.project_id AND m.user_id = $1
+			WHERE n.deleted_at IS NULL AND p.archived_at IS NULL` + conditions + `
+			ORDER BY score DESC, n.updated_at DESC
+			LIMIT $3
+		),
+		ancestors AS (
+			SELECT h.id AS hit_id, e.parent_node_id AS node_id, 1 AS depth
+			FROM hits h
+			INNER JOIN edges e ON e.child_node_id = h.id
+			WHERE e.parent_node_id IS NOT NULL
+			UNION ALL
+			SELECT a.hit_id, e.parent_node_id, a.depth + 1
+			FROM ancestors a
+			INNER JOIN edges e ON e.child_node_id = a.node_id
+			WHERE e.parent_node_id IS NOT NULL AND a.depth < 1000
+		)
+		SELECT h.project_id, h.project_title, h.id, h.content, h.question, h.score,
+		       ARRAY(
+		           SELECT an.content
+		           FROM ancestors a
+		           INNER JOIN nodes an ON an.id = a.node_id
+		           WHERE a.hit_id = h.id
+		           ORDER BY a.depth DESC
+		       ) AS breadcrumb
+		FROM hits h
+		ORDER BY h.score DESC, h.updated_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search nodes: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []model.SearchCandidate
+	for rows.Next() {
+		var c model.SearchCandidate
+		var nodeID uuid.UUID
+		if err := rows.Scan(&c.ProjectID, &c.ProjectTitle, &nodeID, &c.Content, &c.Question, &c.Score, &c.Breadcrumb); err != nil {
+			return nil, fmt.Errorf("failed to scan node search result: %w", err)
+		}
+		c.NodeID = &nodeID
+		candidates = append(candidates, c)
+	}
+	return candidates, nil
+}
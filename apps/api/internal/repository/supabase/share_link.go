@@ -0,0 +1,93 @@
+package supabase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// shareLinkRepository は共有リンクリポジトリのSupabase実装です
+type shareLinkRepository struct {
+	db repository.DBInterface
+}
+
+// NewShareLinkRepository は新しい共有リンクリポジトリを作成します
+func NewShareLinkRepository(db repository.DBInterface) repository.ShareLinkRepository {
+	return &shareLinkRepository{db: db}
+}
+
+func (r *shareLinkRepository) Create(ctx context.Context, projectID, createdBy uuid.UUID, tokenHash string, label *string, expiresAt *time.Time) (*model.ShareLink, error) {
+	var link model.ShareLink
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO share_links (project_id, token_hash, label, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, project_id, label, expires_at, revoked_at, created_at
+	`, projectID, tokenHash, label, createdBy, expiresAt).Scan(
+		&link.ID, &link.ProjectID, &link.Label, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *shareLinkRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.ShareLink, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, project_id, label, expires_at, revoked_at, created_at
+		FROM share_links
+		WHERE project_id = $1
+		ORDER BY created_at DESC
+	`, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []model.ShareLink
+	for rows.Next() {
+		var link model.ShareLink
+		if err := rows.Scan(&link.ID, &link.ProjectID, &link.Label, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan share link: %w", err)
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+// GetActiveByTokenHash は取り消されておらず期限内の共有リンクを返します（該当なしの場合は nil）
+func (r *shareLinkRepository) GetActiveByTokenHash(ctx context.Context, tokenHash string) (*model.ShareLink, error) {
+	var link model.ShareLink
+	err := r.db.QueryRow(ctx, `
+		SELECT id, project_id, label, expires_at, revoked_at, created_at
+		FROM share_links
+		WHERE token_hash = $1
+		  AND revoked_at IS NULL
+		  AND (expires_at IS NULL OR expires_at > NOW())
+	`, tokenHash).Scan(
+		&link.ID, &link.ProjectID, &link.Label, &link.ExpiresAt, &link.RevokedAt, &link.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+	return &link, nil
+}
+
+// Revoke は共有リンクを取り消します（対象がなければ false）
+func (r *shareLinkRepository) Revoke(ctx context.Context, projectID, shareLinkID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE share_links SET revoked_at = NOW()
+		WHERE id = $1 AND project_id = $2 AND revoked_at IS NULL
+	`, shareLinkID, projectID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke share link: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
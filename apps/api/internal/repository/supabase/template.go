@@ -0,0 +1,104 @@
+package supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// templateRepository はテンプレートリポジトリのSupabase実装です
+type templateRepository struct {
+	db repository.DBInterface
+}
+
+// NewTemplateRepository は新しいテンプレートリポジトリを作成します
+func NewTemplateRepository(db repository.DBInterface) repository.TemplateRepository {
+	return &templateRepository{db: db}
+}
+
+func (r *templateRepository) Create(ctx context.Context, userID uuid.UUID, title string, description *string, nodes []model.TemplateNode) (*model.Template, error) {
+	data, err := json.Marshal(nodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal template nodes: %w", err)
+	}
+
+	row := r.db.QueryRow(ctx, `
+		INSERT INTO templates (user_id, title, description, nodes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, user_id, title, description, nodes, created_at
+	`, userID, title, description, data)
+	template, err := scanTemplate(row)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return template, nil
+}
+
+// ListByUserID はユーザーが保存したテンプレートを新しい順に返します
+func (r *templateRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]model.Template, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, title, description, nodes, created_at
+		FROM templates
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []model.Template
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template: %w", err)
+		}
+		templates = append(templates, *template)
+	}
+	return templates, nil
+}
+
+func (r *templateRepository) GetByID(ctx context.Context, templateID uuid.UUID) (*model.Template, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT id, user_id, title, description, nodes, created_at
+		FROM templates
+		WHERE id = $1
+	`, templateID)
+	template, err := scanTemplate(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template: %w", err)
+	}
+	return template, nil
+}
+
+// Delete はユーザー自身のテンプレートを削除します（対象がなければ false）
+func (r *templateRepository) Delete(ctx context.Context, userID, templateID uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM templates WHERE id = $1 AND user_id = $2
+	`, templateID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete template: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func scanTemplate(row pgx.Row) (*model.Template, error) {
+	var template model.Template
+	var data []byte
+	if err := row.Scan(&template.ID, &template.UserID, &template.Title, &template.Description, &data, &template.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &template.Nodes); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal template nodes: %w", err)
+	}
+	return &template, nil
+}
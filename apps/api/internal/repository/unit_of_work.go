@@ -0,0 +1,24 @@
+package repository
+
+import "context"
+
+// Repositories は同じトランザクションを共有するリポジトリの組です
+type Repositories struct {
+	Projects   ProjectRepository
+	Members    MemberRepository
+	Nodes      NodeRepository
+	Edges      EdgeRepository
+	Snapshots  SnapshotRepository
+	Tags       TagRepository
+	Operations OperationRepository
+}
+
+// UnitOfWork は複数のリポジトリにまたがる操作を1つのトランザクションで実行します
+type UnitOfWork interface {
+	// Do は fn に渡したリポジトリでの操作をまとめてコミットします
+	// fn がエラーを返した場合はすべてロールバックされます
+	Do(ctx context.Context, fn func(repos Repositories) error) error
+	// Read は fn に渡したリポジトリでの読み込みを、同じ時点のスナップショットから行います
+	// （REPEATABLE READ の読み取り専用トランザクション）
+	Read(ctx context.Context, fn func(repos Repositories) error) error
+}
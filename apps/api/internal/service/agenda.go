@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type AgendaService struct {
+	nodeRepo repository.NodeRepository
+	feedRepo repository.CalendarFeedRepository
+}
+
+func NewAgendaService(nodeRepo repository.NodeRepository, feedRepo repository.CalendarFeedRepository) *AgendaService {
+	return &AgendaService{
+		nodeRepo: nodeRepo,
+		feedRepo: feedRepo,
+	}
+}
+
+// GetAgenda はアーカイブされていない全プロジェクトから、期限切れまたは days 日以内が期日の未完了ノードを返します
+func (s *AgendaService) GetAgenda(ctx context.Context, userID uuid.UUID, days int, loc *time.Location) (*model.AgendaResponse, error) {
+	today := model.NewDate(time.Now().In(loc))
+	items, err := s.listDueItems(ctx, userID, today)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := today.AddDate(0, 0, days)
+	filtered := []model.AgendaItem{}
+	for _, item := range items {
+		if !item.Node.DueDate.After(limit) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return &model.AgendaResponse{Today: today, Days: days, Items: filtered}, nil
+}
+
+// CreateCalendarFeed はカレンダーフィードのトークンを発行します（既存のトークンは無効になります）
+func (s *AgendaService) CreateCalendarFeed(ctx context.Context, userID uuid.UUID) (*model.CalendarFeedResponse, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.feedRepo.Upsert(ctx, userID, hashToken(token)); err != nil {
+		return nil, err
+	}
+	return &model.CalendarFeedResponse{Token: token, Path: "/v1/calendar/" + token + "/agenda.ics"}, nil
+}
+
+func (s *AgendaService) RevokeCalendarFeed(ctx context.Context, userID uuid.UUID) error {
+	deleted, err := s.feedRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// GetCalendarFeed はフィードのトークンの持ち主の、期日のある未完了ノードを iCalendar 形式で返します
+func (s *AgendaService) GetCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	userID, err := s.feedRepo.GetUserIDByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if userID == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	now := time.Now()
+	items, err := s.listDueItems(ctx, *userID, model.NewDate(now))
+	if err != nil {
+		return nil, err
+	}
+	return export.ICalendar(items, now), nil
+}
+
+// listDueItems は期日が設定された未完了（done / dropped 以外）のノードを、祖先のパス付きで期日順に返します
+// カレンダーアプリから定期的に取得されるため、プロジェクトの数によらず1回のクエリで読み込みます
+func (s *AgendaService) listDueItems(ctx context.Context, userID uuid.UUID, today model.Date) ([]model.AgendaItem, error) {
+	items, err := s.nodeRepo.ListDueByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		daysUntil := int(items[i].Node.DueDate.Sub(today.Time).Hours() / 24)
+		items[i].Overdue = daysUntil < 0
+		items[i].DaysUntilDue = daysUntil
+	}
+	return items, nil
+}
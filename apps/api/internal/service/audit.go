@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type AuditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record は監査ログに1件追記します
+func (s *AuditService) Record(ctx context.Context, event model.AuditEvent) error {
+	return s.auditRepo.Create(ctx, event)
+}
+
+// ListEvents はユーザー自身の監査ログを新しい順に limit 件まで返します
+func (s *AuditService) ListEvents(ctx context.Context, userID uuid.UUID, before *int64, limit int) (*model.AuditListResponse, error) {
+	// 1件多く読み、続きがあるかを判定する
+	events, err := s.auditRepo.ListByUserID(ctx, userID, before, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.AuditListResponse{Events: events}
+	if len(events) > limit {
+		resp.Events = events[:limit]
+		next := resp.Events[limit-1].Seq
+		resp.NextBefore = &next
+	}
+	if resp.Events == nil {
+		resp.Events = []model.AuditEvent{}
+	}
+	return resp, nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// lockNode はノードの行をロックし、expectedVersion が指定されていれば現在のバージョンと照合します
+func lockNode(ctx context.Context, repos repository.Repositories, projectID, nodeID uuid.UUID, expectedVersion *int64) (*model.Node, error) {
+	node, err := repos.Nodes.GetByIDForUpdate(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil || node.ProjectID != projectID {
+		return nil, ErrNodeNotFound
+	}
+	if expectedVersion != nil && node.Version != *expectedVersion {
+		return nil, ErrVersionConflict
+	}
+	return node, nil
+}
+
+// lockProjectRevision はプロジェクトの行をロックし、expectedRevision が指定されていれば現在のリビジョンと照合します
+func lockProjectRevision(ctx context.Context, repos repository.Repositories, projectID uuid.UUID, expectedRevision *int64) error {
+	revision, err := repos.Projects.GetRevisionForUpdate(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if revision == nil {
+		return ErrProjectNotFound
+	}
+	if expectedRevision != nil && *revision != *expectedRevision {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// currentRevision は同じトランザクション内で更新後のプロジェクトのリビジョンを読み直します
+func currentRevision(ctx context.Context, repos repository.Repositories, projectID uuid.UUID) (int64, error) {
+	revision, err := repos.Projects.GetRevisionForUpdate(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	if revision == nil {
+		return 0, ErrProjectNotFound
+	}
+	return *revision, nil
+}
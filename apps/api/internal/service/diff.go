@@ -0,0 +1,80 @@
+package service
+
+import "github.com/mokuhyo-driven-test/api/internal/model"
+
+// maxDiffCells は LCS の表の上限です。超える場合は変わった範囲全体を削除と挿入として返します
+const maxDiffCells = 1 << 20
+
+// diffText は before から after への文字単位の差分を返します
+// 日本語の文章は空白で区切らないため、単語ではなく文字（rune）で比べます
+func diffText(before, after string) []model.DiffSegment {
+	a, b := []rune(before), []rune(after)
+
+	// 共通の先頭と末尾は表に含めない
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var segments []model.DiffSegment
+	appendSegment := func(op model.DiffOp, text []rune) {
+		if len(text) == 0 {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].Op == op {
+			segments[n-1].Text += string(text)
+			return
+		}
+		segments = append(segments, model.DiffSegment{Op: op, Text: string(text)})
+	}
+
+	appendSegment(model.DiffEqual, a[:prefix])
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	if len(midA)*len(midB) > maxDiffCells {
+		appendSegment(model.DiffDelete, midA)
+		appendSegment(model.DiffInsert, midB)
+	} else {
+		// lcs[i][j] は midA[i:] と midB[j:] の最長共通部分列の長さ
+		lcs := make([][]int, len(midA)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(midB)+1)
+		}
+		for i := len(midA) - 1; i >= 0; i-- {
+			for j := len(midB) - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+
+		i, j := 0, 0
+		for i < len(midA) && j < len(midB) {
+			switch {
+			case midA[i] == midB[j]:
+				appendSegment(model.DiffEqual, midA[i:i+1])
+				i++
+				j++
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				appendSegment(model.DiffDelete, midA[i:i+1])
+				i++
+			default:
+				appendSegment(model.DiffInsert, midB[j:j+1])
+				j++
+			}
+		}
+		appendSegment(model.DiffDelete, midA[i:])
+		appendSegment(model.DiffInsert, midB[j:])
+	}
+	appendSegment(model.DiffEqual, a[len(a)-suffix:])
+
+	if segments == nil {
+		segments = []model.DiffSegment{}
+	}
+	return segments
+}
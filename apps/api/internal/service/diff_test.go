@@ -0,0 +1,76 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestDiffText(t *testing.T) {
+	eq := func(text string) model.DiffSegment { return model.DiffSegment{Op: model.DiffEqual, Text: text} }
+	ins := func(text string) model.DiffSegment { return model.DiffSegment{Op: model.DiffInsert, Text: text} }
+	del := func(text string) model.DiffSegment { return model.DiffSegment{Op: model.DiffDelete, Text: text} }
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []model.DiffSegment
+	}{
+		{name: "both empty", before: "", after: "", want: []model.DiffSegment{}},
+		{name: "unchanged", before: "目標", after: "目標", want: []model.DiffSegment{eq("目標")}},
+		{name: "from empty", before: "", after: "新規", want: []model.DiffSegment{ins("新規")}},
+		{name: "to empty", before: "削除", after: "", want: []model.DiffSegment{del("削除")}},
+		{name: "append", before: "英語を話す", after: "英語を毎日話す", want: []model.DiffSegment{eq("英語を"), ins("毎日"), eq("話す")}},
+		{name: "replace middle", before: "毎朝走る", after: "毎晩走る", want: []model.DiffSegment{eq("毎"), del("朝"), ins("晩"), eq("走る")}},
+		{name: "delete suffix", before: "本を10冊読む", after: "本を10冊", want: []model.DiffSegment{eq("本を10冊"), del("読む")}},
+		{
+			name:   "interleaved",
+			before: "abcd",
+			after:  "axcy",
+			want:   []model.DiffSegment{eq("a"), del("b"), ins("x"), eq("c"), del("d"), ins("y")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffText(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffText(%q, %q) = %v, want %v", tt.before, tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDiffTextReconstructs は差分から before と after の両方を復元できることを確かめます
+// 表が maxDiffCells を超える場合の、変わった範囲全体を置き換える経路も含めます
+func TestDiffTextReconstructs(t *testing.T) {
+	long := strings.Repeat("あい", 800)
+	tests := []struct {
+		before string
+		after  string
+	}{
+		{before: "kitten", after: "sitting"},
+		{before: "問いを立てる", after: "良い問いを立て続ける"},
+		{before: "x" + long + "y", after: "x" + strings.Repeat("いあ", 800) + "y"},
+	}
+
+	for _, tt := range tests {
+		var before, after strings.Builder
+		for _, segment := range diffText(tt.before, tt.after) {
+			switch segment.Op {
+			case model.DiffEqual:
+				before.WriteString(segment.Text)
+				after.WriteString(segment.Text)
+			case model.DiffDelete:
+				before.WriteString(segment.Text)
+			case model.DiffInsert:
+				after.WriteString(segment.Text)
+			}
+		}
+		if before.String() != tt.before || after.String() != tt.after {
+			t.Errorf("reconstructed (%q, %q), want (%q, %q)", before.String(), after.String(), tt.before, tt.after)
+		}
+	}
+}
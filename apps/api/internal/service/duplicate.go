@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// DuplicateProject はプロジェクトのツリー（ノード・エッジ・タグ）を新しいIDで複製し、userID をオーナーとする新しいプロジェクトを作成します
+func (s *ProjectService) DuplicateProject(ctx context.Context, userID, projectID uuid.UUID, req model.DuplicateProjectRequest) (*model.Project, error) {
+	return s.copyTree(ctx, userID, projectID, nil, req, false)
+}
+
+// ExtractSubtree はノードと子孫を、そのノードをルートとする新しいプロジェクトとして複製します
+// req.DeleteSource が true の場合は、同じトランザクションで元のノードを子孫ごと論理削除します
+func (s *ProjectService) ExtractSubtree(ctx context.Context, userID, projectID, nodeID uuid.UUID, req model.ExtractSubtreeRequest) (*model.Project, error) {
+	return s.copyTree(ctx, userID, projectID, &nodeID, req.DuplicateProjectRequest, req.DeleteSource)
+}
+
+// copyTree は rootNodeID 以下（nil の場合はツリー全体）を新しいプロジェクトへ1つのトランザクションで複製します
+func (s *ProjectService) copyTree(ctx context.Context, userID, projectID uuid.UUID, rootNodeID *uuid.UUID, req model.DuplicateProjectRequest, deleteSource bool) (*model.Project, error) {
+	var project *model.Project
+	var sourceRevision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// 複製の途中で元のツリーが変わらないよう、元のプロジェクトをロックする
+		if err := lockProjectRevision(ctx, repos, projectID, nil); err != nil {
+			return err
+		}
+		tree, err := loadTree(ctx, repos, projectID)
+		if err != nil {
+			return err
+		}
+
+		nodes, edges, idMap, err := copyNodes(tree, rootNodeID, req.ResetProgress)
+		if err != nil {
+			return err
+		}
+		if len(nodes) > maxImportNodes {
+			return fmt.Errorf("%w: too many nodes (max %d)", ErrInvalidDuplicate, maxImportNodes)
+		}
+
+		createReq := model.CreateProjectRequest{Description: req.Description}
+		var rootContent *string
+		if rootNodeID != nil {
+			rootContent = &nodes[0].Content
+		}
+		createReq.Title = copyTitle(req.Title, tree.Project.Title, rootContent)
+		if n := utf8.RuneCountInString(createReq.Title); n < 3 || n > 20 {
+			return fmt.Errorf("%w: title must be between 3 and 20 characters", ErrInvalidDuplicate)
+		}
+		if createReq.Description == nil && rootNodeID == nil {
+			createReq.Description = tree.Project.Description
+		}
+
+		project, err = repos.Projects.Create(ctx, userID, createReq)
+		if err != nil {
+			return err
+		}
+		if _, _, err := repos.Nodes.CreateSubtree(ctx, project.ID, nodes, edges); err != nil {
+			return err
+		}
+		if err := copyTags(ctx, repos, projectID, project.ID, idMap); err != nil {
+			return err
+		}
+
+		if deleteSource {
+			if sourceRevision, err = removeExtractedNode(ctx, repos, userID, projectID, *rootNodeID); err != nil {
+				return err
+			}
+		}
+
+		// ノードとエッジの作成で進んだリビジョンを返す
+		project, err = repos.Projects.GetByID(ctx, project.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if deleteSource {
+		publishEvent(ctx, s.broker, projectID, sourceRevision, events.TypeNodeDeleted, model.NodeRefEvent{NodeID: *rootNodeID})
+	}
+	return project, nil
+}
+
+// copyTitle は複製先のプロジェクトのタイトルを決めます
+// title が省略された場合、複製では元のタイトルに「のコピー」を付け、切り出し（rootContent が nil でない場合）ではノードの内容を使います
+// ノードの内容が空か3文字に満たない場合は、元のプロジェクトのタイトルで代用します
+func copyTitle(title *string, projectTitle string, rootContent *string) string {
+	switch {
+	case title != nil:
+		return strings.TrimSpace(*title)
+	case rootContent == nil:
+		return trimToRunes(projectTitle+"のコピー", 20)
+	}
+	extracted := strings.TrimSpace(trimToRunes(strings.TrimSpace(*rootContent), 20))
+	if utf8.RuneCountInString(extracted) < 3 {
+		return trimToRunes(projectTitle, 20)
+	}
+	return extracted
+}
+
+// copyNodes は rootNodeID 以下のノードとエッジを新しいIDで複製します（先頭が複製後のルートです）
+// 兄弟の並び順は保ったまま 0 から振り直し、idMap は元のノードIDから新しいノードIDへの対応です
+func copyNodes(tree *model.TreeResponse, rootNodeID *uuid.UUID, resetProgress bool) ([]model.Node, []model.Edge, map[uuid.UUID]uuid.UUID, error) {
+	nodeByID := make(map[uuid.UUID]model.Node, len(tree.Nodes))
+	for _, node := range tree.Nodes {
+		nodeByID[node.ID] = node
+	}
+
+	// エッジは order_index 順に並んでいる
+	var roots []model.Edge
+	children := make(map[uuid.UUID][]model.Edge)
+	for _, edge := range tree.Edges {
+		switch {
+		case rootNodeID != nil && edge.ChildNodeID == *rootNodeID:
+			roots = append(roots, edge)
+		case edge.ParentNodeID == nil:
+			if rootNodeID == nil {
+				roots = append(roots, edge)
+			}
+		default:
+			children[*edge.ParentNodeID] = append(children[*edge.ParentNodeID], edge)
+		}
+	}
+	if rootNodeID != nil && len(roots) == 0 {
+		return nil, nil, nil, ErrNodeNotFound
+	}
+	if len(roots) == 0 {
+		return nil, nil, nil, fmt.Errorf("%w: project has no nodes", ErrInvalidDuplicate)
+	}
+
+	var nodes []model.Node
+	var edges []model.Edge
+	idMap := make(map[uuid.UUID]uuid.UUID, len(tree.Nodes))
+	var walk func(list []model.Edge, parentID *uuid.UUID)
+	walk = func(list []model.Edge, parentID *uuid.UUID) {
+		for i, edge := range list {
+			node, ok := nodeByID[edge.ChildNodeID]
+			if !ok {
+				continue
+			}
+			if _, seen := idMap[node.ID]; seen {
+				continue
+			}
+			newID := uuid.New()
+			idMap[node.ID] = newID
+
+			copied := model.Node{ID: newID, Content: node.Content, Question: node.Question, Status: node.Status, DueDate: node.DueDate, Weight: node.Weight}
+			if resetProgress {
+				copied.Status = model.NodeStatusTodo
+				copied.DueDate = nil
+			}
+			nodes = append(nodes, copied)
+
+			copiedEdge := model.Edge{ParentNodeID: parentID, ChildNodeID: newID, Relation: edge.Relation, RelationLabel: edge.RelationLabel, OrderIndex: i}
+			if parentID == nil {
+				// 切り出したノードは新しいプロジェクトのルートになる
+				copiedEdge.Relation = model.RelationNeutral
+				copiedEdge.RelationLabel = nil
+			}
+			edges = append(edges, copiedEdge)
+
+			walk(children[node.ID], &newID)
+		}
+	}
+	walk(roots, nil)
+	return nodes, edges, idMap, nil
+}
+
+// copyTags は複製したノードに付いているタグを新しいプロジェクトに作成し、付け直します
+func copyTags(ctx context.Context, repos repository.Repositories, sourceProjectID, projectID uuid.UUID, idMap map[uuid.UUID]uuid.UUID) error {
+	nodeTags, err := repos.Tags.ListNodeTags(ctx, sourceProjectID)
+	if err != nil {
+		return err
+	}
+	tagIDsByNode := make(map[uuid.UUID][]uuid.UUID)
+	used := make(map[uuid.UUID]struct{})
+	for _, nt := range nodeTags {
+		if _, ok := idMap[nt.NodeID]; !ok {
+			continue
+		}
+		tagIDsByNode[nt.NodeID] = append(tagIDsByNode[nt.NodeID], nt.TagID)
+		used[nt.TagID] = struct{}{}
+	}
+	if len(used) == 0 {
+		return nil
+	}
+
+	tags, err := repos.Tags.ListByProjectID(ctx, sourceProjectID)
+	if err != nil {
+		return err
+	}
+	tagMap := make(map[uuid.UUID]uuid.UUID, len(used))
+	for _, tag := range tags {
+		if _, ok := used[tag.ID]; !ok {
+			continue
+		}
+		created, err := repos.Tags.Create(ctx, projectID, model.CreateTagRequest{Name: tag.Name, Color: tag.Color})
+		if err != nil {
+			return err
+		}
+		tagMap[tag.ID] = created.ID
+	}
+
+	for nodeID, tagIDs := range tagIDsByNode {
+		newTagIDs := make([]uuid.UUID, 0, len(tagIDs))
+		for _, tagID := range tagIDs {
+			newTagIDs = append(newTagIDs, tagMap[tagID])
+		}
+		if err := repos.Tags.SetNodeTags(ctx, projectID, idMap[nodeID], newTagIDs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeExtractedNode は切り出したノードを子孫ごと論理削除し、取り消せるよう操作履歴に記録します
+func removeExtractedNode(ctx context.Context, repos repository.Repositories, userID, projectID, nodeID uuid.UUID) (int64, error) {
+	edge, err := repos.Edges.GetByChildNodeID(ctx, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	if edge == nil || edge.ParentNodeID == nil {
+		return 0, fmt.Errorf("%w: the root node cannot be removed from its project", ErrInvalidDuplicate)
+	}
+	siblings, err := childOrder(ctx, repos, projectID, edge.ParentNodeID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := repos.Nodes.SoftDeleteWithDescendants(ctx, projectID, nodeID); err != nil {
+		return 0, err
+	}
+	before := model.OperationValue{ParentNodeID: edge.ParentNodeID, OrderedChildNodeIDs: siblings}
+	if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeDelete, []uuid.UUID{nodeID}, before, model.OperationValue{}); err != nil {
+		return 0, err
+	}
+	return currentRevision(ctx, repos, projectID)
+}
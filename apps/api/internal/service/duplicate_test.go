@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// duplicateSourceTree は root の下に a（order_index 2）と b（order_index 5）、a の下に a1 を持つツリーです
+// copyNodes の前提どおり、エッジは order_index 順に並べます
+func duplicateSourceTree() (*model.TreeResponse, map[string]uuid.UUID) {
+	ids := map[string]uuid.UUID{"root": uuid.New(), "a": uuid.New(), "b": uuid.New(), "a1": uuid.New()}
+	due := model.NewDate(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC))
+	weight := 2.0
+	root, a := ids["root"], ids["a"]
+	return &model.TreeResponse{
+		Nodes: []model.Node{
+			{ID: ids["root"], Content: "root", Status: model.NodeStatusDoing},
+			{ID: ids["a"], Content: "a", Question: strPtr("どうやって？"), Status: model.NodeStatusDone, DueDate: &due, Weight: &weight},
+			{ID: ids["b"], Content: "b", Status: model.NodeStatusTodo},
+			{ID: ids["a1"], Content: "a1", Status: model.NodeStatusDropped},
+		},
+		Edges: []model.Edge{
+			{ChildNodeID: ids["root"], Relation: model.RelationNeutral},
+			{ParentNodeID: &root, ChildNodeID: ids["a"], Relation: model.RelationCustom, RelationLabel: strPtr("手段"), OrderIndex: 2},
+			{ParentNodeID: &root, ChildNodeID: ids["b"], Relation: model.RelationWhy, OrderIndex: 5},
+			{ParentNodeID: &a, ChildNodeID: ids["a1"], Relation: model.RelationHow, OrderIndex: 3},
+		},
+	}, ids
+}
+
+func TestCopyNodes(t *testing.T) {
+	tests := []struct {
+		name          string
+		root          string
+		resetProgress bool
+		// want は「内容|親の内容|関係|order_index|状態|期日あり」の行で、先頭が複製後のルートです
+		want []string
+	}{
+		{
+			name: "whole project",
+			want: []string{
+				"root|-|neutral|0|doing|false",
+				"a|root|custom|0|done|true",
+				"a1|a|how|0|dropped|false",
+				"b|root|why|1|todo|false",
+			},
+		},
+		{
+			name:          "whole project with reset progress",
+			resetProgress: true,
+			want: []string{
+				"root|-|neutral|0|todo|false",
+				"a|root|custom|0|todo|false",
+				"a1|a|how|0|todo|false",
+				"b|root|why|1|todo|false",
+			},
+		},
+		{
+			name: "subtree becomes root",
+			root: "a",
+			want: []string{
+				"a|-|neutral|0|done|true",
+				"a1|a|how|0|dropped|false",
+			},
+		},
+		{
+			name: "leaf subtree",
+			root: "b",
+			want: []string{"b|-|neutral|0|todo|false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, ids := duplicateSourceTree()
+			var rootNodeID *uuid.UUID
+			if tt.root != "" {
+				id := ids[tt.root]
+				rootNodeID = &id
+			}
+
+			nodes, edges, idMap, err := copyNodes(tree, rootNodeID, tt.resetProgress)
+			if err != nil {
+				t.Fatalf("copyNodes() error = %v", err)
+			}
+			if len(nodes) != len(edges) || len(idMap) != len(nodes) {
+				t.Fatalf("got %d nodes, %d edges and %d mappings", len(nodes), len(edges), len(idMap))
+			}
+
+			contentByID := make(map[uuid.UUID]string, len(nodes))
+			for _, node := range nodes {
+				contentByID[node.ID] = node.Content
+				if idMap[ids[node.Content]] != node.ID {
+					t.Errorf("idMap does not map %s to its copy", node.Content)
+				}
+				if node.ID == ids[node.Content] {
+					t.Errorf("%s kept its source ID", node.Content)
+				}
+			}
+
+			var got []string
+			for i, node := range nodes {
+				edge := edges[i]
+				if edge.ChildNodeID != node.ID {
+					t.Fatalf("edges[%d] does not belong to nodes[%d]", i, i)
+				}
+				parent := "-"
+				if edge.ParentNodeID != nil {
+					parent = contentByID[*edge.ParentNodeID]
+				}
+				got = append(got, fmt.Sprintf("%s|%s|%s|%d|%s|%v", node.Content, parent, edge.Relation, edge.OrderIndex, node.Status, node.DueDate != nil))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("copyNodes() =\n%v\nwant\n%v", got, tt.want)
+			}
+			if edges[0].RelationLabel != nil {
+				t.Errorf("root relation label = %q, want nil", *edges[0].RelationLabel)
+			}
+		})
+	}
+}
+
+func TestCopyNodesErrors(t *testing.T) {
+	tree, _ := duplicateSourceTree()
+	missing := uuid.New()
+
+	tests := []struct {
+		name    string
+		tree    *model.TreeResponse
+		root    *uuid.UUID
+		wantErr error
+	}{
+		{name: "unknown root node", tree: tree, root: &missing, wantErr: ErrNodeNotFound},
+		{name: "empty project", tree: &model.TreeResponse{}, wantErr: ErrInvalidDuplicate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := copyNodes(tt.tree, tt.root, false); !errors.Is(err, tt.wantErr) {
+				t.Errorf("copyNodes() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCopyTitle(t *testing.T) {
+	tests := []struct {
+		name        string
+		title       *string
+		rootContent *string
+		want        string
+	}{
+		{name: "explicit title", title: strPtr("  新しい計画  "), rootContent: strPtr("英会話"), want: "新しい計画"},
+		{name: "duplicate", want: "英語学習のコピー"},
+		{name: "extract uses node content", rootContent: strPtr("  オンライン英会話  "), want: "オンライン英会話"},
+		{name: "long content is trimmed", rootContent: strPtr("毎日三十分だけ英語のポッドキャストを聞いて声に出す"), want: "毎日三十分だけ英語のポッドキャストを聞い"},
+		{name: "blank content falls back to project title", rootContent: strPtr(" \n "), want: "英語学習"},
+		{name: "short content falls back to project title", rootContent: strPtr("走る"), want: "英語学習"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := copyTitle(tt.title, "英語学習", tt.rootContent); got != tt.want {
+				t.Errorf("copyTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
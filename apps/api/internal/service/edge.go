@@ -4,22 +4,88 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
 	"github.com/mokuhyo-driven-test/api/internal/model"
 	"github.com/mokuhyo-driven-test/api/internal/repository"
 )
 
 type EdgeService struct {
 	edgeRepo repository.EdgeRepository
+	uow      repository.UnitOfWork
+	broker   *events.Broker
 }
 
-func NewEdgeService(edgeRepo repository.EdgeRepository) *EdgeService {
-	return &EdgeService{edgeRepo: edgeRepo}
+func NewEdgeService(edgeRepo repository.EdgeRepository, uow repository.UnitOfWork, broker *events.Broker) *EdgeService {
+	return &EdgeService{edgeRepo: edgeRepo, uow: uow, broker: broker}
 }
 
-func (s *EdgeService) UpdateEdge(ctx context.Context, edgeID uuid.UUID, req model.UpdateEdgeRequest) error {
-	return s.edgeRepo.Update(ctx, edgeID, req.Relation, req.RelationLabel)
+// UpdateEdge は関係を更新し、更新後のプロジェクトのリビジョンを返します
+func (s *EdgeService) UpdateEdge(ctx context.Context, userID, projectID, edgeID uuid.UUID, req model.UpdateEdgeRequest, expectedRevision *int64) (int64, error) {
+	var edge *model.Edge
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, expectedRevision); err != nil {
+			return err
+		}
+		current, err := repos.Edges.GetByID(ctx, edgeID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.ProjectID != projectID {
+			return ErrEdgeNotFound
+		}
+		if err := repos.Edges.Update(ctx, edgeID, req.Relation, req.RelationLabel); err != nil {
+			return err
+		}
+		if edge, err = repos.Edges.GetByID(ctx, edgeID); err != nil {
+			return err
+		}
+
+		before := model.OperationValue{EdgeID: &edgeID, Relation: &current.Relation, RelationLabel: current.RelationLabel}
+		after := model.OperationValue{EdgeID: &edgeID, Relation: &edge.Relation, RelationLabel: edge.RelationLabel}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationEdgeUpdate, []uuid.UUID{edge.ChildNodeID}, before, after); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeEdgeUpdated, model.EdgeEvent{Edge: *edge})
+	return revision, nil
 }
 
-func (s *EdgeService) Reorder(ctx context.Context, projectID uuid.UUID, req model.ReorderRequest) error {
-	return s.edgeRepo.Reorder(ctx, projectID, req.ParentNodeID, req.OrderedChildNodeIDs)
+// Reorder は兄弟の並び順を更新し、更新後のプロジェクトのリビジョンを返します
+func (s *EdgeService) Reorder(ctx context.Context, userID, projectID uuid.UUID, req model.ReorderRequest, expectedRevision *int64) (int64, error) {
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, expectedRevision); err != nil {
+			return err
+		}
+		previous, err := childOrder(ctx, repos, projectID, req.ParentNodeID)
+		if err != nil {
+			return err
+		}
+		if err := repos.Edges.Reorder(ctx, projectID, req.ParentNodeID, req.OrderedChildNodeIDs); err != nil {
+			return err
+		}
+		before := model.OperationValue{ParentNodeID: req.ParentNodeID, OrderedChildNodeIDs: previous}
+		after := model.OperationValue{ParentNodeID: req.ParentNodeID, OrderedChildNodeIDs: req.OrderedChildNodeIDs}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationReorder, nil, before, after); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeReordered, model.ReorderedEvent{
+		ParentNodeID:        req.ParentNodeID,
+		OrderedChildNodeIDs: req.OrderedChildNodeIDs,
+	})
+	return revision, nil
 }
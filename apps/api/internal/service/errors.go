@@ -0,0 +1,44 @@
+package service
+
+import "errors"
+
+var (
+	ErrNodeNotFound = errors.New("node not found")
+	ErrInvalidMove  = errors.New("invalid move")
+
+	ErrInvalidSuggestionCount = errors.New("invalid suggestion count")
+
+	ErrEdgeNotFound = errors.New("edge not found")
+
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrNothingToRedo = errors.New("nothing to redo")
+	ErrUndoConflict  = errors.New("the tree has changed since this operation; it was dropped from the history")
+
+	ErrNodeNotDeleted  = errors.New("node is not deleted")
+	ErrParentIsDeleted = errors.New("parent node is deleted; restore the parent first")
+
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+	ErrRevisionNotFound = errors.New("revision not found")
+
+	ErrInvalidImport    = errors.New("invalid import")
+	ErrInvalidDuplicate = errors.New("invalid duplicate")
+
+	ErrProjectNotFound = errors.New("project not found")
+
+	ErrTemplateNotFound  = errors.New("template not found")
+	ErrTemplateIsBuiltin = errors.New("built-in templates cannot be changed or deleted")
+	ErrInvalidTemplate   = errors.New("invalid template")
+
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagNameRequired = errors.New("tag name must not be blank")
+	ErrTagNameTaken    = errors.New("a tag with the same name already exists in this project")
+
+	ErrShareLinkNotFound    = errors.New("share link not found or expired")
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+	ErrUserNotFound     = errors.New("user not found; they need to sign in once before being invited")
+	ErrMemberNotFound   = errors.New("member not found")
+	ErrAlreadyMember    = errors.New("user is already a member of this project")
+	ErrOwnerIsImmutable = errors.New("the project owner cannot be changed or removed")
+	ErrVersionConflict  = errors.New("resource has been modified; reload and retry")
+)
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+)
+
+// publishEvent はコミット済みの変更をプロジェクトの購読者に通知します（broker がない場合は何もしません）
+func publishEvent(ctx context.Context, broker *events.Broker, projectID uuid.UUID, revision int64, eventType string, data interface{}) {
+	if broker == nil {
+		return
+	}
+	broker.Publish(ctx, events.Event{
+		Type:      eventType,
+		ProjectID: projectID,
+		Revision:  revision,
+		Data:      data,
+	})
+}
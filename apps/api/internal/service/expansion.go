@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/ai"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+const (
+	defaultExpandDepth   = 1
+	defaultExpandBreadth = 3
+	// maxDraftNodes は1回の展開で提案するノード数の上限です（AI呼び出し回数の上限にもなります）
+	maxDraftNodes = 40
+)
+
+// ExpandNode は親ノードの下に作る子ノード案を depth 階層・各 breadth 件まで生成します
+// 結果は保存せずに返し、クライアントが採用したものだけを AcceptDraft で作成します
+func (s *NodeService) ExpandNode(ctx context.Context, projectID, parentNodeID uuid.UUID, req model.ExpandNodeRequest) (*model.ExpandNodeResponse, error) {
+	parent, err := s.nodeRepo.GetByID(ctx, parentNodeID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil || parent.ProjectID != projectID {
+		return nil, ErrNodeNotFound
+	}
+
+	depth := req.Depth
+	if depth <= 0 {
+		depth = defaultExpandDepth
+	}
+	breadth := req.Breadth
+	if breadth <= 0 {
+		breadth = defaultExpandBreadth
+	}
+
+	qc, err := s.loadQuestionContext(ctx, projectID, parentNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	textGenerator, _ := s.questionGenerator.(ai.TextGenerator)
+	budget := maxDraftNodes
+	children := s.proposeChildren(ctx, textGenerator, qc, depth, breadth, &budget)
+	if children == nil {
+		children = []model.DraftNode{}
+	}
+
+	return &model.ExpandNodeResponse{ParentNodeID: parentNodeID, Children: children}, nil
+}
+
+// proposeChildren は qc.parent の子ノード案を生成し、depth が残っていれば各案をさらに展開します
+func (s *NodeService) proposeChildren(ctx context.Context, generator ai.TextGenerator, qc *questionContext, depth, breadth int, budget *int) []model.DraftNode {
+	if depth <= 0 || *budget <= 0 {
+		return nil
+	}
+
+	var drafts []model.DraftNode
+	if generator != nil {
+		raw, err := generator.GenerateText(ctx, buildExpansionPrompt(qc, breadth))
+		if err == nil {
+			drafts = parseDraftProposals(raw, qc, breadth)
+		}
+	}
+	if len(drafts) == 0 {
+		// AIが使えない場合は、保存済みの親に対してだけ観点ごとの質問を持つ空の子ノード案を返す
+		if qc.parent.ID == uuid.Nil {
+			return nil
+		}
+		drafts = fallbackDrafts(qc, breadth)
+		depth = 1
+	}
+	if len(drafts) > *budget {
+		drafts = drafts[:*budget]
+	}
+	*budget -= len(drafts)
+
+	if depth > 1 {
+		for i := range drafts {
+			childContext := &questionContext{
+				parent:      model.Node{Content: drafts[i].Content, Question: drafts[i].Question},
+				ancestors:   append([]model.Node{qc.parent}, qc.ancestors...),
+				edgeByChild: qc.edgeByChild,
+			}
+			drafts[i].Children = s.proposeChildren(ctx, generator, childContext, depth-1, breadth, budget)
+		}
+	}
+	return drafts
+}
+
+func buildExpansionPrompt(qc *questionContext, breadth int) string {
+	var builder strings.Builder
+	builder.WriteString("あなたは目標を分解して具体的な行動に落とし込むアシスタントです。\n")
+	builder.WriteString(fmt.Sprintf("親ノードの目標を分解する子ノードを最大%d個提案してください。\n", breadth))
+	builder.WriteString("出力は次の形式のJSON配列のみとし、説明やコードブロックは付けないでください。\n")
+	builder.WriteString(`[{"content": "子ノードの内容（200字以内）", "question": "その子ノードを導く質問（30字以内、？で終える）", "relation": "why|how|what|concrete のいずれか"}]`)
+	builder.WriteString("\n")
+	builder.WriteString("- 既存の子ノードと同じ内容は避ける\n")
+	builder.WriteString("- 子ノード同士で内容や質問が重複しないようにする\n\n")
+	builder.WriteString("親ノード:\n")
+	builder.WriteString(formatNodeLine(qc.parent, qc.edgeByChild[qc.parent.ID]))
+	builder.WriteString("\n\n")
+	builder.WriteString("祖先ノード（親を除く、近い順）:\n")
+	if len(qc.ancestors) == 0 {
+		builder.WriteString("- なし\n")
+	} else {
+		for _, node := range qc.ancestors {
+			builder.WriteString("- " + formatNodeLine(node, qc.edgeByChild[node.ID]) + "\n")
+		}
+	}
+	builder.WriteString("\n")
+	builder.WriteString("既存の子ノード:\n")
+	if len(qc.siblings) == 0 {
+		builder.WriteString("- なし\n")
+	} else {
+		for _, node := range qc.siblings {
+			builder.WriteString("- " + formatNodeLine(node, qc.edgeByChild[node.ID]) + "\n")
+		}
+	}
+	return builder.String()
+}
+
+type draftProposal struct {
+	Content  string `json:"content"`
+	Question string `json:"question"`
+	Relation string `json:"relation"`
+}
+
+// parseDraftProposals はAIの出力からJSON配列を取り出し、制約を満たす子ノード案に整えます
+func parseDraftProposals(raw string, qc *questionContext, breadth int) []model.DraftNode {
+	start := strings.Index(raw, "[")
+	end := strings.LastIndex(raw, "]")
+	if start < 0 || end <= start {
+		return nil
+	}
+	var proposals []draftProposal
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &proposals); err != nil {
+		return nil
+	}
+
+	taken := append([]model.Node{}, qc.siblings...)
+	seenContent := make(map[string]struct{}, len(proposals))
+	for _, node := range qc.siblings {
+		seenContent[normalizePlainText(node.Content)] = struct{}{}
+	}
+
+	var drafts []model.DraftNode
+	for _, proposal := range proposals {
+		if len(drafts) >= breadth {
+			break
+		}
+		content := trimToRunes(strings.TrimSpace(proposal.Content), 200)
+		key := normalizePlainText(content)
+		if key == "" {
+			continue
+		}
+		if _, dup := seenContent[key]; dup {
+			continue
+		}
+		seenContent[key] = struct{}{}
+
+		relation := model.RelationType(strings.ToLower(strings.TrimSpace(proposal.Relation)))
+		if !isValidRelation(relation) {
+			relation = model.RelationNeutral
+		}
+
+		question := normalizeQuestion(proposal.Question)
+		if !isQuestionUsable(question, qc.parent, qc.ancestors, taken) {
+			question = fallbackQuestionText(&qc.parent, qc.ancestors, taken)
+		}
+		taken = append(taken, model.Node{Content: content, Question: &question})
+
+		drafts = append(drafts, model.DraftNode{Content: content, Question: &question, Relation: relation})
+	}
+	return drafts
+}
+
+func fallbackDrafts(qc *questionContext, breadth int) []model.DraftNode {
+	var drafts []model.DraftNode
+	taken := append([]model.Node{}, qc.siblings...)
+	for _, relation := range suggestionRelations {
+		if len(drafts) >= breadth {
+			break
+		}
+		for _, candidate := range fallbackCandidatesByFocus(string(relation)) {
+			if !isQuestionUsable(candidate, qc.parent, qc.ancestors, taken) {
+				continue
+			}
+			question := candidate
+			taken = append(taken, model.Node{Question: &question})
+			drafts = append(drafts, model.DraftNode{Question: &question, Relation: relation})
+			break
+		}
+	}
+	return drafts
+}
+
+// AcceptDraft は採用された子ノード案を、既存の子の末尾に続けて1つのトランザクションで作成します
+func (s *NodeService) AcceptDraft(ctx context.Context, userID, projectID, parentNodeID uuid.UUID, req model.AcceptDraftRequest) ([]model.Node, []model.Edge, error) {
+	roots := make([]*export.OutlineNode, 0, len(req.Children))
+	for _, draft := range req.Children {
+		roots = append(roots, draftToOutline(draft))
+	}
+
+	// 末尾の位置の取得と作成を同じトランザクションで行う
+	var nodes []model.Node
+	var edges []model.Edge
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// 採用中に親が削除・移動されないよう、同じトランザクションでロックして確認する
+		if _, err := lockNode(ctx, repos, projectID, parentNodeID, nil); err != nil {
+			return err
+		}
+
+		firstOrderIndex, err := repos.Nodes.GetMaxOrderIndex(ctx, projectID, &parentNodeID)
+		if err != nil {
+			return fmt.Errorf("failed to get max order index: %w", err)
+		}
+
+		drafts, draftEdges := flattenOutline(roots, &parentNodeID, firstOrderIndex)
+		if len(drafts) > maxImportNodes {
+			return fmt.Errorf("%w: too many nodes (max %d)", ErrInvalidImport, maxImportNodes)
+		}
+		if err := validateImportedNodes(drafts, draftEdges); err != nil {
+			return err
+		}
+
+		// クライアントが以降の更新で使えるよう、version などを含む作成後の行を返す
+		nodes, edges, err = repos.Nodes.CreateSubtree(ctx, projectID, drafts, draftEdges)
+		if err != nil {
+			return err
+		}
+
+		// 取り消すときは採用した案の最上位のノードを子孫ごと削除する
+		var topNodeIDs []uuid.UUID
+		for _, edge := range edges {
+			if sameNodeID(edge.ParentNodeID, &parentNodeID) {
+				topNodeIDs = append(topNodeIDs, edge.ChildNodeID)
+			}
+		}
+		siblings, err := childOrder(ctx, repos, projectID, &parentNodeID)
+		if err != nil {
+			return err
+		}
+		after := model.OperationValue{ParentNodeID: &parentNodeID, OrderedChildNodeIDs: siblings}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodesCreate, topNodeIDs, model.OperationValue{}, after); err != nil {
+			return err
+		}
+
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodesCreated, model.NodesCreatedEvent{Nodes: nodes, Edges: edges})
+	return nodes, edges, nil
+}
+
+func draftToOutline(draft model.DraftNode) *export.OutlineNode {
+	node := &export.OutlineNode{
+		Node: model.Node{Content: draft.Content, Question: draft.Question},
+		Edge: model.Edge{Relation: draft.Relation, RelationLabel: draft.RelationLabel},
+	}
+	for _, child := range draft.Children {
+		node.Children = append(node.Children, draftToOutline(child))
+	}
+	return node
+}
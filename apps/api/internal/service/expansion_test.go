@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/ai"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// rawTextGenerator は常に raw をそのまま返します
+type rawTextGenerator struct {
+	raw string
+}
+
+func (g rawTextGenerator) GenerateText(ctx context.Context, prompt string) (string, error) {
+	return g.raw, nil
+}
+
+// draftLines は子ノード案を「深さ|内容|関係|質問あり」の行に平らにします
+func draftLines(drafts []model.DraftNode) []string {
+	var lines []string
+	var walk func(list []model.DraftNode, depth int)
+	walk = func(list []model.DraftNode, depth int) {
+		for _, draft := range list {
+			lines = append(lines, fmt.Sprintf("%d|%s|%s|%v", depth, draft.Content, draft.Relation, draft.Question != nil))
+			walk(draft.Children, depth+1)
+		}
+	}
+	walk(drafts, 0)
+	return lines
+}
+
+func TestParseDraftProposals(t *testing.T) {
+	existing := "既存の子"
+	qc := &questionContext{
+		parent:   model.Node{ID: uuid.New(), Content: "英語を話せるようになる"},
+		siblings: []model.Node{{Content: existing}},
+	}
+
+	tests := []struct {
+		name    string
+		raw     string
+		breadth int
+		want    []string
+	}{
+		{name: "no array", raw: "提案できません", breadth: 3, want: nil},
+		{name: "malformed json", raw: `[{"content": "単語を覚える",]`, breadth: 3, want: nil},
+		{name: "brackets in wrong order", raw: `] 説明 [`, breadth: 3, want: nil},
+		{
+			name:    "array wrapped in prose and code fence",
+			raw:     "以下が提案です。\n```json\n[{\"content\": \"単語を覚える\", \"question\": \"何語覚える？\", \"relation\": \"how\"}]\n```",
+			breadth: 3,
+			want:    []string{"0|単語を覚える|how|true"},
+		},
+		{
+			name: "duplicate content is dropped",
+			raw: `[{"content": "単語を覚える", "relation": "how"},
+				{"content": " 単語を覚える？ ", "relation": "what"},
+				{"content": "既存の子", "relation": "why"},
+				{"content": "  ", "relation": "why"},
+				{"content": "発音を練習する", "relation": "concrete"}]`,
+			breadth: 5,
+			want:    []string{"0|単語を覚える|how|true", "0|発音を練習する|concrete|true"},
+		},
+		{
+			name:    "unknown relation falls back to neutral",
+			raw:     `[{"content": "A", "relation": "because"}, {"content": "B", "relation": " WHY "}, {"content": "C"}]`,
+			breadth: 3,
+			want:    []string{"0|A|neutral|true", "0|B|why|true", "0|C|neutral|true"},
+		},
+		{
+			name:    "breadth limits the result",
+			raw:     `[{"content": "A"}, {"content": "B"}, {"content": "C"}]`,
+			breadth: 2,
+			want:    []string{"0|A|neutral|true", "0|B|neutral|true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			drafts := parseDraftProposals(tt.raw, qc, tt.breadth)
+			if got := draftLines(drafts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDraftProposals() = %q, want %q", got, tt.want)
+			}
+			seen := make(map[string]bool)
+			for _, draft := range drafts {
+				if !isQuestionUsable(*draft.Question, qc.parent, nil, nil) || seen[*draft.Question] {
+					t.Errorf("question %q is unusable or duplicated", *draft.Question)
+				}
+				seen[*draft.Question] = true
+			}
+		})
+	}
+}
+
+func TestParseDraftProposalsReplacesUnusableQuestions(t *testing.T) {
+	qc := &questionContext{parent: model.Node{Content: "英語を話せるようになる"}}
+	raw := `[{"content": "A", "question": "同じ質問？"}, {"content": "B", "question": "同じ質問？"}, {"content": "C", "question": ""}]`
+
+	drafts := parseDraftProposals(raw, qc, 3)
+	if len(drafts) != 3 {
+		t.Fatalf("got %d drafts, want 3", len(drafts))
+	}
+	if *drafts[0].Question != "同じ質問？" {
+		t.Errorf("first question = %q, want it kept", *drafts[0].Question)
+	}
+	if *drafts[1].Question == "同じ質問？" || *drafts[2].Question == "" || *drafts[1].Question == *drafts[2].Question {
+		t.Errorf("duplicate or empty questions were not replaced: %q, %q", *drafts[1].Question, *drafts[2].Question)
+	}
+}
+
+func TestProposeChildren(t *testing.T) {
+	saved := model.Node{ID: uuid.New(), Content: "英語を話せるようになる"}
+	unsaved := model.Node{Content: "まだ保存されていない案"}
+
+	tests := []struct {
+		name      string
+		generator ai.TextGenerator
+		parent    model.Node
+		depth     int
+		breadth   int
+		budget    int
+		// wantPerDepth は深さごとのノード数です
+		wantPerDepth []int
+		wantBudget   int
+	}{
+		{
+			name:         "fake generator one level",
+			generator:    ai.NewFakeQuestionGenerator(),
+			parent:       saved,
+			depth:        1,
+			breadth:      3,
+			budget:       maxDraftNodes,
+			wantPerDepth: []int{3},
+			wantBudget:   maxDraftNodes - 3,
+		},
+		{
+			name:         "budget runs out in the middle of the third level",
+			generator:    ai.NewFakeQuestionGenerator(),
+			parent:       saved,
+			depth:        3,
+			breadth:      4,
+			budget:       maxDraftNodes,
+			wantPerDepth: []int{4, 8, 28},
+			wantBudget:   0,
+		},
+		{
+			name:         "budget smaller than breadth",
+			generator:    ai.NewFakeQuestionGenerator(),
+			parent:       saved,
+			depth:        2,
+			breadth:      4,
+			budget:       6,
+			wantPerDepth: []int{4, 2},
+			wantBudget:   0,
+		},
+		{
+			name:         "malformed output falls back to one level for a saved parent",
+			generator:    rawTextGenerator{raw: "申し訳ありませんが提案できません"},
+			parent:       saved,
+			depth:        3,
+			breadth:      2,
+			budget:       maxDraftNodes,
+			wantPerDepth: []int{2},
+			wantBudget:   maxDraftNodes - 2,
+		},
+		{
+			name:         "no generator falls back",
+			parent:       saved,
+			depth:        2,
+			breadth:      4,
+			budget:       maxDraftNodes,
+			wantPerDepth: []int{4},
+			wantBudget:   maxDraftNodes - 4,
+		},
+		{
+			name:         "malformed output under an unsaved draft yields nothing",
+			generator:    rawTextGenerator{raw: "[not json]"},
+			parent:       unsaved,
+			depth:        2,
+			breadth:      3,
+			budget:       maxDraftNodes,
+			wantPerDepth: nil,
+			wantBudget:   maxDraftNodes,
+		},
+		{
+			name:         "no budget",
+			generator:    ai.NewFakeQuestionGenerator(),
+			parent:       saved,
+			depth:        1,
+			breadth:      3,
+			budget:       0,
+			wantPerDepth: nil,
+			wantBudget:   0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &NodeService{}
+			budget := tt.budget
+			drafts := svc.proposeChildren(context.Background(), tt.generator, &questionContext{parent: tt.parent}, tt.depth, tt.breadth, &budget)
+
+			var perDepth []int
+			for _, line := range draftLines(drafts) {
+				depth := int(line[0] - '0')
+				for len(perDepth) <= depth {
+					perDepth = append(perDepth, 0)
+				}
+				perDepth[depth]++
+			}
+			if !reflect.DeepEqual(perDepth, tt.wantPerDepth) {
+				t.Errorf("nodes per depth = %v, want %v\n%s", perDepth, tt.wantPerDepth, strings.Join(draftLines(drafts), "\n"))
+			}
+			if budget != tt.wantBudget {
+				t.Errorf("remaining budget = %d, want %d", budget, tt.wantBudget)
+			}
+		})
+	}
+}
+
+func TestExpandNode(t *testing.T) {
+	svc, projectID, parentID := newQuestionTestService(ai.NewFakeQuestionGenerator())
+
+	resp, err := svc.ExpandNode(context.Background(), projectID, parentID, model.ExpandNodeRequest{Depth: 2, Breadth: 2})
+	if err != nil {
+		t.Fatalf("ExpandNode() error = %v", err)
+	}
+	if resp.ParentNodeID != parentID {
+		t.Errorf("ParentNodeID = %s, want %s", resp.ParentNodeID, parentID)
+	}
+	if got := len(draftLines(resp.Children)); got != 6 {
+		t.Errorf("got %d drafts, want 6 (2 children with 2 grandchildren each)", got)
+	}
+	for _, draft := range resp.Children {
+		if !isValidRelation(draft.Relation) {
+			t.Errorf("draft %q has invalid relation %q", draft.Content, draft.Relation)
+		}
+	}
+}
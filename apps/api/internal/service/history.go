@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// HistoryService はユーザーごとの操作履歴をたどって取り消し・やり直しを行います
+type HistoryService struct {
+	uow    repository.UnitOfWork
+	broker *events.Broker
+}
+
+func NewHistoryService(uow repository.UnitOfWork, broker *events.Broker) *HistoryService {
+	return &HistoryService{uow: uow, broker: broker}
+}
+
+// Undo はユーザーが最後に行った操作を取り消します
+// 操作後にツリーが変わっていて戻せない場合は、その操作を履歴から外して ErrUndoConflict を返します
+func (s *HistoryService) Undo(ctx context.Context, userID, projectID uuid.UUID) (*model.HistoryResponse, error) {
+	return s.step(ctx, userID, projectID, false)
+}
+
+// Redo はユーザーが最後に取り消した操作をやり直します
+func (s *HistoryService) Redo(ctx context.Context, userID, projectID uuid.UUID) (*model.HistoryResponse, error) {
+	return s.step(ctx, userID, projectID, true)
+}
+
+func (s *HistoryService) step(ctx context.Context, userID, projectID uuid.UUID, forward bool) (*model.HistoryResponse, error) {
+	var op *model.Operation
+	var revision int64
+	conflict := false
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// 同じプロジェクトへの他の変更と直列化する
+		if err := lockProjectRevision(ctx, repos, projectID, nil); err != nil {
+			return err
+		}
+
+		var err error
+		if forward {
+			op, err = repos.Operations.GetFirstUndone(ctx, projectID, userID)
+		} else {
+			op, err = repos.Operations.GetLastDone(ctx, projectID, userID)
+		}
+		if err != nil {
+			return err
+		}
+		if op == nil {
+			if forward {
+				return ErrNothingToRedo
+			}
+			return ErrNothingToUndo
+		}
+
+		// 変更を始める前に確認し、食い違っていれば操作を破棄した状態でコミットする
+		if err := verifyOperation(ctx, repos, op, forward); err != nil {
+			if !errors.Is(err, ErrUndoConflict) {
+				return err
+			}
+			conflict = true
+			return repos.Operations.SetState(ctx, op.ID, model.OperationDiscarded)
+		}
+		if err := applyOperation(ctx, repos, op, forward); err != nil {
+			return err
+		}
+
+		op.State = model.OperationUndone
+		if forward {
+			op.State = model.OperationDone
+		}
+		if err := repos.Operations.SetState(ctx, op.ID, op.State); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		return nil, ErrUndoConflict
+	}
+
+	eventType := events.TypeOperationUndone
+	if forward {
+		eventType = events.TypeOperationRedone
+	}
+	publishEvent(ctx, s.broker, projectID, revision, eventType, model.OperationEvent{Operation: *op})
+	return &model.HistoryResponse{OK: true, Operation: *op, Revision: revision}, nil
+}
+
+// operationStates は操作を適用する向きに応じた適用前・適用後の状態を返します
+func operationStates(op *model.Operation, forward bool) (from, to model.OperationValue) {
+	if forward {
+		return op.Before, op.After
+	}
+	return op.After, op.Before
+}
+
+// revivesNodes は操作を適用するとノードが復活するか（false なら削除されるか）を返します
+func revivesNodes(op *model.Operation, forward bool) bool {
+	switch op.Kind {
+	case model.OperationNodeCreate, model.OperationNodesCreate, model.OperationNodeRestore:
+		return forward
+	default:
+		return !forward
+	}
+}
+
+// verifyOperation は現在のツリーが操作の適用前の状態と一致するかを確認します
+func verifyOperation(ctx context.Context, repos repository.Repositories, op *model.Operation, forward bool) error {
+	from, to := operationStates(op, forward)
+
+	switch op.Kind {
+	case model.OperationNodeCreate, model.OperationNodesCreate, model.OperationNodeDelete, model.OperationNodeRestore:
+		revive := revivesNodes(op, forward)
+		for _, nodeID := range op.NodeIDs {
+			node, err := repos.Nodes.GetByIDIncludingDeleted(ctx, nodeID)
+			if err != nil {
+				return err
+			}
+			if node == nil || node.ProjectID != op.ProjectID || (node.DeletedAt != nil) != revive {
+				return ErrUndoConflict
+			}
+		}
+		if revive && to.ParentNodeID != nil {
+			parent, err := repos.Nodes.GetByID(ctx, *to.ParentNodeID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return ErrUndoConflict
+			}
+		}
+
+	case model.OperationNodeUpdate, model.OperationNodeRevert, model.OperationNodeStatus:
+		for _, nodeID := range op.NodeIDs {
+			node, err := lockNode(ctx, repos, op.ProjectID, nodeID, nil)
+			if errors.Is(err, ErrNodeNotFound) {
+				return ErrUndoConflict
+			}
+			if err != nil {
+				return err
+			}
+			if op.Kind == model.OperationNodeUpdate && (from.Content == nil || node.Content != *from.Content) {
+				return ErrUndoConflict
+			}
+			if op.Kind == model.OperationNodeRevert && (from.Content == nil || node.Content != *from.Content || !sameString(node.Question, from.Question)) {
+				return ErrUndoConflict
+			}
+			if op.Kind == model.OperationNodeStatus && (from.Status == nil || node.Status != *from.Status ||
+				!sameDate(node.DueDate, from.DueDate) || !sameFloat(node.Weight, from.Weight)) {
+				return ErrUndoConflict
+			}
+		}
+
+	case model.OperationNodeMove:
+		if len(op.NodeIDs) != 1 || to.ParentNodeID == nil {
+			return ErrUndoConflict
+		}
+		nodeID := op.NodeIDs[0]
+		node, err := repos.Nodes.GetByID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		parent, err := repos.Nodes.GetByID(ctx, *to.ParentNodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil || parent == nil {
+			return ErrUndoConflict
+		}
+		edges, err := repos.Edges.ListByProjectID(ctx, op.ProjectID)
+		if err != nil {
+			return fmt.Errorf("failed to list edges: %w", err)
+		}
+		parentByChild := make(map[uuid.UUID]*uuid.UUID, len(edges))
+		for _, edge := range edges {
+			parentByChild[edge.ChildNodeID] = edge.ParentNodeID
+		}
+		if !sameNodeID(parentByChild[nodeID], from.ParentNodeID) || createsCycle(parentByChild, nodeID, *to.ParentNodeID) {
+			return ErrUndoConflict
+		}
+
+	case model.OperationEdgeUpdate:
+		if from.EdgeID == nil || from.Relation == nil {
+			return ErrUndoConflict
+		}
+		edge, err := repos.Edges.GetByID(ctx, *from.EdgeID)
+		if err != nil {
+			return err
+		}
+		if edge == nil || edge.ProjectID != op.ProjectID || edge.Relation != *from.Relation || !sameString(edge.RelationLabel, from.RelationLabel) {
+			return ErrUndoConflict
+		}
+
+	case model.OperationReorder:
+		// 並び順は記録後に増減した子も含めて戻せるため、食い違いを確認しない
+
+	default:
+		return ErrUndoConflict
+	}
+	return nil
+}
+
+// applyOperation は verifyOperation で確認した操作をツリーに適用します
+func applyOperation(ctx context.Context, repos repository.Repositories, op *model.Operation, forward bool) error {
+	_, to := operationStates(op, forward)
+
+	switch op.Kind {
+	case model.OperationNodeCreate, model.OperationNodesCreate, model.OperationNodeDelete, model.OperationNodeRestore:
+		if !revivesNodes(op, forward) {
+			for _, nodeID := range op.NodeIDs {
+				if err := repos.Nodes.SoftDeleteWithDescendants(ctx, op.ProjectID, nodeID); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, nodeID := range op.NodeIDs {
+			if err := repos.Nodes.RestoreWithDescendants(ctx, op.ProjectID, nodeID); err != nil {
+				return err
+			}
+		}
+		return restoreOrder(ctx, repos, op.ProjectID, to.ParentNodeID, to.OrderedChildNodeIDs)
+
+	case model.OperationNodeUpdate:
+		for _, nodeID := range op.NodeIDs {
+			if err := repos.Nodes.Update(ctx, nodeID, *to.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case model.OperationNodeRevert:
+		for _, nodeID := range op.NodeIDs {
+			if err := repos.Nodes.UpdateText(ctx, nodeID, *to.Content, to.Question); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case model.OperationNodeStatus:
+		for _, nodeID := range op.NodeIDs {
+			if err := repos.Nodes.UpdateStatus(ctx, nodeID, *to.Status, to.DueDate, to.Weight); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case model.OperationNodeMove:
+		if err := repos.Edges.Move(ctx, op.ProjectID, op.NodeIDs[0], to.ParentNodeID, -1); err != nil {
+			return err
+		}
+		return restoreOrder(ctx, repos, op.ProjectID, to.ParentNodeID, to.OrderedChildNodeIDs)
+
+	case model.OperationEdgeUpdate:
+		return repos.Edges.SetRelation(ctx, *to.EdgeID, *to.Relation, to.RelationLabel)
+
+	case model.OperationReorder:
+		return restoreOrder(ctx, repos, op.ProjectID, to.ParentNodeID, to.OrderedChildNodeIDs)
+	}
+	return nil
+}
+
+func sameString(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameDate(a, b *model.Date) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.String() == b.String()
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
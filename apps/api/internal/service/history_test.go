@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+func TestHistoryUndoRedoOrder(t *testing.T) {
+	ctx := context.Background()
+	projectID, userID, root := uuid.New(), uuid.New(), uuid.New()
+	x, y, z := uuid.New(), uuid.New(), uuid.New()
+
+	edgeRepo := &stubEdgeRepo{}
+	for i, id := range []uuid.UUID{x, y, z} {
+		edgeRepo.edges = append(edgeRepo.edges, model.Edge{ID: uuid.New(), ProjectID: projectID, ParentNodeID: &root, ChildNodeID: id, OrderIndex: i})
+	}
+	operations := &memoryOperationRepo{}
+	repos := repository.Repositories{Projects: &stubProjectRepo{revision: 1}, Edges: edgeRepo, Operations: operations}
+	svc := NewHistoryService(stubUnitOfWork{repos: repos}, nil)
+
+	// reorder は並べ替えを行い、その操作を履歴に記録します
+	reorder := func(after []uuid.UUID) uuid.UUID {
+		t.Helper()
+		before, err := childOrder(ctx, repos, projectID, &root)
+		if err != nil {
+			t.Fatalf("childOrder() error = %v", err)
+		}
+		if err := edgeRepo.Reorder(ctx, projectID, &root, after); err != nil {
+			t.Fatalf("Reorder() error = %v", err)
+		}
+		err = recordOperation(ctx, repos, projectID, userID, model.OperationReorder, nil,
+			model.OperationValue{ParentNodeID: &root, OrderedChildNodeIDs: before},
+			model.OperationValue{ParentNodeID: &root, OrderedChildNodeIDs: after})
+		if err != nil {
+			t.Fatalf("recordOperation() error = %v", err)
+		}
+		return operations.ops[len(operations.ops)-1].ID
+	}
+	assertOrder := func(want ...uuid.UUID) {
+		t.Helper()
+		got, err := childOrder(ctx, repos, projectID, &root)
+		if err != nil {
+			t.Fatalf("childOrder() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("children = %v, want %v", got, want)
+		}
+	}
+	step := func(forward bool, wantID uuid.UUID) {
+		t.Helper()
+		name, run := "Undo", svc.Undo
+		if forward {
+			name, run = "Redo", svc.Redo
+		}
+		res, err := run(ctx, userID, projectID)
+		if err != nil {
+			t.Fatalf("%s() error = %v", name, err)
+		}
+		if res.Operation.ID != wantID {
+			t.Errorf("%s() operation seq = %d, want operation %s", name, res.Operation.Seq, wantID)
+		}
+	}
+
+	opA := reorder([]uuid.UUID{y, x, z})
+	opB := reorder([]uuid.UUID{y, z, x})
+	opC := reorder([]uuid.UUID{z, y, x})
+
+	step(false, opC)
+	assertOrder(y, z, x)
+	step(false, opB)
+	assertOrder(y, x, z)
+
+	// やり直しは最も古い取り消し済みの操作から順に行う
+	step(true, opB)
+	assertOrder(y, z, x)
+
+	// 新しい操作を行うと、やり直し待ちの操作は破棄される
+	opD := reorder([]uuid.UUID{x, z, y})
+	if _, err := svc.Redo(ctx, userID, projectID); !errors.Is(err, ErrNothingToRedo) {
+		t.Fatalf("Redo() error = %v, want ErrNothingToRedo", err)
+	}
+	for _, op := range operations.ops {
+		if op.ID == opC && op.State != model.OperationDiscarded {
+			t.Errorf("operation C state = %s, want %s", op.State, model.OperationDiscarded)
+		}
+	}
+
+	step(false, opD)
+	assertOrder(y, z, x)
+	step(false, opB)
+	step(false, opA)
+	assertOrder(x, y, z)
+	if _, err := svc.Undo(ctx, userID, projectID); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo() error = %v, want ErrNothingToUndo", err)
+	}
+}
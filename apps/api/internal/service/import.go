@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// maxImportNodes は1回のインポートで作成できるノード数の上限です
+const maxImportNodes = 5000
+
+// ImportProject はドキュメントを読み込み、新しいプロジェクトとして1つのトランザクションで作成します
+func (s *ProjectService) ImportProject(ctx context.Context, userID uuid.UUID, format string, data []byte) (*model.Project, error) {
+	tree, err := export.Parse(format, data)
+	if err != nil {
+		return nil, err
+	}
+	return s.createProjectFromTree(ctx, userID, tree)
+}
+
+func (s *ProjectService) createProjectFromTree(ctx context.Context, userID uuid.UUID, tree *export.Tree) (*model.Project, error) {
+	title := strings.TrimSpace(tree.Title)
+	if title == "" && len(tree.Roots) > 0 {
+		title = trimToRunes(strings.TrimSpace(tree.Roots[0].Node.Content), 20)
+	}
+	if n := utf8.RuneCountInString(title); n < 3 || n > 20 {
+		return nil, fmt.Errorf("%w: title must be between 3 and 20 characters", ErrInvalidImport)
+	}
+	if len(tree.Roots) == 0 {
+		return nil, fmt.Errorf("%w: document has no nodes", ErrInvalidImport)
+	}
+
+	nodes, edges := flattenOutline(tree.Roots, nil, 0)
+	if len(nodes) > maxImportNodes {
+		return nil, fmt.Errorf("%w: too many nodes (max %d)", ErrInvalidImport, maxImportNodes)
+	}
+	if err := validateImportedNodes(nodes, edges); err != nil {
+		return nil, err
+	}
+
+	req := model.CreateProjectRequest{Title: title, Description: tree.Description}
+	return s.projectRepo.CreateWithTree(ctx, userID, req, nodes, edges)
+}
+
+// flattenOutline は入れ子のツリーに新しいIDを振り、ノードとエッジの一覧に変換します
+// 最上位のノードは parentID の子として firstOrderIndex から順に並べます
+func flattenOutline(roots []*export.OutlineNode, parentID *uuid.UUID, firstOrderIndex int) ([]model.Node, []model.Edge) {
+	var nodes []model.Node
+	var edges []model.Edge
+
+	var walk func(list []*export.OutlineNode, parentID *uuid.UUID, offset int)
+	walk = func(list []*export.OutlineNode, parentID *uuid.UUID, offset int) {
+		for i, item := range list {
+			node := item.Node
+			node.ID = uuid.New()
+			nodes = append(nodes, node)
+
+			edge := item.Edge
+			edge.ParentNodeID = parentID
+			edge.ChildNodeID = node.ID
+			edge.OrderIndex = offset + i
+			if edge.Relation == "" {
+				edge.Relation = model.RelationNeutral
+			}
+			edges = append(edges, edge)
+
+			id := node.ID
+			walk(item.Children, &id, 0)
+		}
+	}
+	walk(roots, parentID, firstOrderIndex)
+	return nodes, edges
+}
+
+// validateImportedNodes は CreateNodeRequest や DB の制約と同じ長さ制限と、ルートが1つだけであることを確認します
+// ツリー・達成率・複製などはプロジェクトのルートが1つであることを前提にしているため、どの形式でも同じ規則で確認します
+func validateImportedNodes(nodes []model.Node, edges []model.Edge) error {
+	roots := 0
+	for _, edge := range edges {
+		if edge.ParentNodeID == nil {
+			roots++
+		}
+	}
+	if roots > 1 {
+		return fmt.Errorf("%w: tree must have exactly one root", ErrInvalidImport)
+	}
+	for _, node := range nodes {
+		if utf8.RuneCountInString(node.Content) > 200 {
+			return fmt.Errorf("%w: node content must be at most 200 characters", ErrInvalidImport)
+		}
+		if node.Question != nil && utf8.RuneCountInString(*node.Question) > 30 {
+			return fmt.Errorf("%w: node question must be at most 30 characters", ErrInvalidImport)
+		}
+	}
+	for _, edge := range edges {
+		if !isValidRelation(edge.Relation) {
+			return fmt.Errorf("%w: unknown relation %q", ErrInvalidImport, edge.Relation)
+		}
+		if edge.RelationLabel != nil && utf8.RuneCountInString(*edge.RelationLabel) > 20 {
+			return fmt.Errorf("%w: relation label must be at most 20 characters", ErrInvalidImport)
+		}
+	}
+	return nil
+}
+
+func isValidRelation(relation model.RelationType) bool {
+	switch relation {
+	case model.RelationNeutral, model.RelationWhy, model.RelationConcrete,
+		model.RelationHow, model.RelationWhat, model.RelationCustom:
+		return true
+	}
+	return false
+}
+
+func trimToRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
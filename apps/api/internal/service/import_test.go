@@ -0,0 +1,118 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestFlattenOutline(t *testing.T) {
+	parentID := uuid.New()
+	roots := []*export.OutlineNode{
+		{Node: model.Node{Content: "A"}, Children: []*export.OutlineNode{
+			{Node: model.Node{Content: "A-1"}, Edge: model.Edge{Relation: model.RelationWhy}},
+			{Node: model.Node{Content: "A-2"}},
+		}},
+		{Node: model.Node{Content: "B"}},
+	}
+
+	nodes, edges := flattenOutline(roots, &parentID, 3)
+	if len(nodes) != 4 || len(edges) != 4 {
+		t.Fatalf("got %d nodes and %d edges, want 4 and 4", len(nodes), len(edges))
+	}
+
+	contentByID := make(map[uuid.UUID]string, len(nodes))
+	for i, node := range nodes {
+		contentByID[node.ID] = node.Content
+		if edges[i].ChildNodeID != node.ID {
+			t.Errorf("edges[%d].ChildNodeID does not match nodes[%d]", i, i)
+		}
+	}
+
+	tests := []struct {
+		content    string
+		parent     string
+		relation   model.RelationType
+		orderIndex int
+	}{
+		{content: "A", parent: "(parent)", relation: model.RelationNeutral, orderIndex: 3},
+		{content: "A-1", parent: "A", relation: model.RelationWhy, orderIndex: 0},
+		{content: "A-2", parent: "A", relation: model.RelationNeutral, orderIndex: 1},
+		{content: "B", parent: "(parent)", relation: model.RelationNeutral, orderIndex: 4},
+	}
+	for i, tt := range tests {
+		edge := edges[i]
+		parent := "(parent)"
+		if *edge.ParentNodeID != parentID {
+			parent = contentByID[*edge.ParentNodeID]
+		}
+		if nodes[i].Content != tt.content || parent != tt.parent || edge.Relation != tt.relation || edge.OrderIndex != tt.orderIndex {
+			t.Errorf("#%d = (%s, parent %s, %s, %d), want (%s, parent %s, %s, %d)", i,
+				nodes[i].Content, parent, edge.Relation, edge.OrderIndex,
+				tt.content, tt.parent, tt.relation, tt.orderIndex)
+		}
+	}
+}
+
+func TestValidateImportedNodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		roots   []*export.OutlineNode
+		wantErr bool
+	}{
+		{
+			name: "single root",
+			roots: []*export.OutlineNode{{Node: model.Node{Content: "根"}, Children: []*export.OutlineNode{
+				{Node: model.Node{Content: "子", Question: strPtr("なぜ？")}, Edge: model.Edge{Relation: model.RelationCustom, RelationLabel: strPtr("理由")}},
+			}}},
+		},
+		{
+			name:    "two roots",
+			roots:   []*export.OutlineNode{{Node: model.Node{Content: "A"}}, {Node: model.Node{Content: "B"}}},
+			wantErr: true,
+		},
+		{
+			name:  "content at limit",
+			roots: []*export.OutlineNode{{Node: model.Node{Content: strings.Repeat("あ", 200)}}},
+		},
+		{
+			name:    "content too long",
+			roots:   []*export.OutlineNode{{Node: model.Node{Content: strings.Repeat("あ", 201)}}},
+			wantErr: true,
+		},
+		{
+			name:    "question too long",
+			roots:   []*export.OutlineNode{{Node: model.Node{Content: "根", Question: strPtr(strings.Repeat("?", 31))}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown relation",
+			roots:   []*export.OutlineNode{{Node: model.Node{Content: "根"}, Edge: model.Edge{Relation: "because"}}},
+			wantErr: true,
+		},
+		{
+			name:    "relation label too long",
+			roots:   []*export.OutlineNode{{Node: model.Node{Content: "根"}, Edge: model.Edge{RelationLabel: strPtr(strings.Repeat("ラ", 21))}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, edges := flattenOutline(tt.roots, nil, 0)
+			err := validateImportedNodes(nodes, edges)
+			if tt.wantErr && !errors.Is(err, ErrInvalidImport) {
+				t.Errorf("validateImportedNodes() error = %v, want ErrInvalidImport", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("validateImportedNodes() error = %v", err)
+			}
+		})
+	}
+}
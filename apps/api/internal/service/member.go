@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type MemberService struct {
+	memberRepo repository.MemberRepository
+	userRepo   repository.UserRepository
+	uow        repository.UnitOfWork
+}
+
+func NewMemberService(memberRepo repository.MemberRepository, userRepo repository.UserRepository, uow repository.UnitOfWork) *MemberService {
+	return &MemberService{
+		memberRepo: memberRepo,
+		userRepo:   userRepo,
+		uow:        uow,
+	}
+}
+
+func (s *MemberService) ListMembers(ctx context.Context, projectID uuid.UUID) ([]model.ProjectMember, error) {
+	members, err := s.memberRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if members == nil {
+		members = []model.ProjectMember{}
+	}
+	return members, nil
+}
+
+// InviteMember はメールアドレスで登録済みのユーザーを探し、指定した役割でメンバーに追加します
+func (s *MemberService) InviteMember(ctx context.Context, projectID, invitedBy uuid.UUID, req model.InviteMemberRequest) (*model.ProjectMember, error) {
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	var member *model.ProjectMember
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		role, err := repos.Members.GetRole(ctx, projectID, user.ID)
+		if err != nil {
+			return err
+		}
+		if role != nil {
+			return ErrAlreadyMember
+		}
+		if err := repos.Members.Add(ctx, projectID, user.ID, req.Role, &invitedBy); err != nil {
+			return err
+		}
+		member, err = findMember(ctx, repos, projectID, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// UpdateMemberRole はメンバーの役割を変更します（オーナーの役割は変更できません）
+func (s *MemberService) UpdateMemberRole(ctx context.Context, projectID, userID uuid.UUID, req model.UpdateMemberRequest) (*model.ProjectMember, error) {
+	var member *model.ProjectMember
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := checkMutableMember(ctx, repos, projectID, userID); err != nil {
+			return err
+		}
+		if err := repos.Members.UpdateRole(ctx, projectID, userID, req.Role); err != nil {
+			return err
+		}
+		var err error
+		member, err = findMember(ctx, repos, projectID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// RemoveMember はメンバーを外します（オーナーは外せません）
+func (s *MemberService) RemoveMember(ctx context.Context, projectID, userID uuid.UUID) error {
+	return s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := checkMutableMember(ctx, repos, projectID, userID); err != nil {
+			return err
+		}
+		return repos.Members.Remove(ctx, projectID, userID)
+	})
+}
+
+func checkMutableMember(ctx context.Context, repos repository.Repositories, projectID, userID uuid.UUID) error {
+	role, err := repos.Members.GetRole(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return ErrMemberNotFound
+	}
+	if *role == model.RoleOwner {
+		return ErrOwnerIsImmutable
+	}
+	return nil
+}
+
+func findMember(ctx context.Context, repos repository.Repositories, projectID, userID uuid.UUID) (*model.ProjectMember, error) {
+	members, err := repos.Members.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range members {
+		if members[i].UserID == userID {
+			return &members[i], nil
+		}
+	}
+	return nil, ErrMemberNotFound
+}
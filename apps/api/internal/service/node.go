@@ -8,30 +8,39 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/mokuhyo-driven-test/api/internal/ai"
+	"github.com/mokuhyo-driven-test/api/internal/events"
 	"github.com/mokuhyo-driven-test/api/internal/model"
 	"github.com/mokuhyo-driven-test/api/internal/repository"
 )
 
 type NodeService struct {
-	nodeRepo         repository.NodeRepository
-	edgeRepo         repository.EdgeRepository
+	nodeRepo          repository.NodeRepository
+	edgeRepo          repository.EdgeRepository
 	questionGenerator ai.QuestionGenerator
+	broker            *events.Broker
+	uow               repository.UnitOfWork
 }
 
-func NewNodeService(nodeRepo repository.NodeRepository, edgeRepo repository.EdgeRepository, questionGenerator ai.QuestionGenerator) *NodeService {
+func NewNodeService(nodeRepo repository.NodeRepository, edgeRepo repository.EdgeRepository, questionGenerator ai.QuestionGenerator, broker *events.Broker, uow repository.UnitOfWork) *NodeService {
 	return &NodeService{
 		nodeRepo:          nodeRepo,
 		edgeRepo:          edgeRepo,
 		questionGenerator: questionGenerator,
+		broker:            broker,
+		uow:               uow,
 	}
 }
 
-func (s *NodeService) CreateNode(ctx context.Context, projectID uuid.UUID, req model.CreateNodeRequest) (*model.Node, *model.Edge, error) {
+func (s *NodeService) CreateNode(ctx context.Context, userID, projectID uuid.UUID, req model.CreateNodeRequest) (*model.Node, *model.Edge, error) {
 	var question *string
+	generateLater := false
 	if req.ParentNodeID != nil {
 		if req.Question != nil && strings.TrimSpace(*req.Question) != "" {
 			selected := strings.TrimSpace(*req.Question)
 			question = &selected
+		} else if s.questionGenerator != nil && s.broker != nil {
+			// AIの応答を待たずに作成し、質問は生成後にイベントで通知する
+			generateLater = true
 		} else {
 			selected, err := s.generateQuestion(ctx, projectID, *req.ParentNodeID)
 			if err != nil {
@@ -42,55 +51,271 @@ func (s *NodeService) CreateNode(ctx context.Context, projectID uuid.UUID, req m
 		}
 	}
 
-	// Create node
-	node, err := s.nodeRepo.Create(ctx, projectID, req.Content, question)
+	var node *model.Node
+	var edge *model.Edge
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		// Create node
+		var err error
+		node, err = repos.Nodes.Create(ctx, projectID, req.Content, question)
+		if err != nil {
+			return err
+		}
+		if generateLater {
+			// 生成中であることはどのインスタンスからも分かるようノードに保存する
+			pending := model.QuestionStatusPending
+			if err := repos.Nodes.SetQuestionStatus(ctx, node.ID, &pending); err != nil {
+				return err
+			}
+			node.QuestionStatus = pending
+		}
+
+		// Determine order_index
+		orderIndex := 0
+		if req.OrderIndex != nil {
+			orderIndex = *req.OrderIndex
+		} else {
+			maxOrder, err := repos.Nodes.GetMaxOrderIndex(ctx, projectID, req.ParentNodeID)
+			if err != nil {
+				return fmt.Errorf("failed to get max order index: %w", err)
+			}
+			orderIndex = maxOrder
+		}
+
+		// Determine relation
+		relation := model.RelationNeutral
+		if req.Relation != "" {
+			relation = model.RelationType(req.Relation)
+		}
+
+		// Create edge
+		edge, err = repos.Edges.Create(ctx, projectID, req.ParentNodeID, node.ID, relation, req.RelationLabel, orderIndex)
+		if err != nil {
+			return fmt.Errorf("failed to create edge: %w", err)
+		}
+
+		siblings, err := childOrder(ctx, repos, projectID, req.ParentNodeID)
+		if err != nil {
+			return err
+		}
+		after := model.OperationValue{ParentNodeID: req.ParentNodeID, OrderedChildNodeIDs: siblings}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeCreate, []uuid.UUID{node.ID}, model.OperationValue{}, after); err != nil {
+			return err
+		}
+
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Determine order_index
-	orderIndex := 0
-	if req.OrderIndex != nil {
-		orderIndex = *req.OrderIndex
-	} else {
-		maxOrder, err := s.nodeRepo.GetMaxOrderIndex(ctx, projectID, req.ParentNodeID)
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeCreated, model.NodeEvent{Node: *node, Edge: edge})
+	if generateLater {
+		s.generateQuestionAsync(ctx, projectID, *req.ParentNodeID, node.ID)
+	}
+
+	return node, edge, nil
+}
+
+// UpdateNode はノードの内容を更新します。expectedVersion が現在のバージョンと異なる場合は ErrVersionConflict を返します
+func (s *NodeService) UpdateNode(ctx context.Context, userID, projectID, nodeID uuid.UUID, req model.UpdateNodeRequest, expectedVersion *int64) (*model.Node, error) {
+	var node *model.Node
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		current, err := lockNode(ctx, repos, projectID, nodeID, expectedVersion)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get max order index: %w", err)
+			return err
+		}
+		if err := repos.Nodes.Update(ctx, nodeID, req.Content); err != nil {
+			return err
+		}
+		before := model.OperationValue{Content: &current.Content}
+		after := model.OperationValue{Content: &req.Content}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeUpdate, []uuid.UUID{nodeID}, before, after); err != nil {
+			return err
 		}
-		orderIndex = maxOrder
+		if node, err = repos.Nodes.GetByID(ctx, nodeID); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Determine relation
-	relation := model.RelationNeutral
-	if req.Relation != "" {
-		relation = model.RelationType(req.Relation)
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeUpdated, model.NodeEvent{Node: *node})
+	return node, nil
+}
+
+// UpdateNodeStatus はノードの状態・期日・重みを更新します。expectedVersion が現在のバージョンと異なる場合は ErrVersionConflict を返します
+func (s *NodeService) UpdateNodeStatus(ctx context.Context, userID, projectID, nodeID uuid.UUID, req model.UpdateNodeStatusRequest, expectedVersion *int64) (*model.Node, error) {
+	var node *model.Node
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		current, err := lockNode(ctx, repos, projectID, nodeID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if err := repos.Nodes.UpdateStatus(ctx, nodeID, req.Status, req.DueDate, req.Weight); err != nil {
+			return err
+		}
+		before := model.OperationValue{Status: &current.Status, DueDate: current.DueDate, Weight: current.Weight}
+		after := model.OperationValue{Status: &req.Status, DueDate: req.DueDate, Weight: req.Weight}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeStatus, []uuid.UUID{nodeID}, before, after); err != nil {
+			return err
+		}
+		if node, err = repos.Nodes.GetByID(ctx, nodeID); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Create edge
-	edge, err := s.edgeRepo.Create(ctx, projectID, req.ParentNodeID, node.ID, relation, req.RelationLabel, orderIndex)
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeUpdated, model.NodeEvent{Node: *node})
+	return node, nil
+}
+
+// DeleteNode はノードを子孫ごと論理削除します。expectedVersion が現在のバージョンと異なる場合は ErrVersionConflict を返します
+func (s *NodeService) DeleteNode(ctx context.Context, userID, projectID, nodeID uuid.UUID, expectedVersion *int64) error {
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if _, err := lockNode(ctx, repos, projectID, nodeID, expectedVersion); err != nil {
+			return err
+		}
+		edge, err := repos.Edges.GetByChildNodeID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		var before model.OperationValue
+		if edge != nil {
+			siblings, err := childOrder(ctx, repos, projectID, edge.ParentNodeID)
+			if err != nil {
+				return err
+			}
+			before = model.OperationValue{ParentNodeID: edge.ParentNodeID, OrderedChildNodeIDs: siblings}
+		}
+		if err := repos.Nodes.SoftDeleteWithDescendants(ctx, projectID, nodeID); err != nil {
+			return err
+		}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeDelete, []uuid.UUID{nodeID}, before, model.OperationValue{}); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to create edge: %w", err)
+		return err
 	}
 
-	return node, edge, nil
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeDeleted, model.NodeRefEvent{NodeID: nodeID})
+	return nil
 }
 
-func (s *NodeService) UpdateNode(ctx context.Context, nodeID uuid.UUID, req model.UpdateNodeRequest) error {
-	return s.nodeRepo.Update(ctx, nodeID, req.Content)
+func (s *NodeService) MoveNode(ctx context.Context, userID, projectID, nodeID uuid.UUID, req model.MoveNodeRequest) error {
+	// 循環の確認と移動を同じトランザクションで行う
+	var moved model.NodeMovedEvent
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		node, err := repos.Nodes.GetByID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil || node.ProjectID != projectID {
+			return ErrNodeNotFound
+		}
+		parent, err := repos.Nodes.GetByID(ctx, req.ParentNodeID)
+		if err != nil {
+			return err
+		}
+		if parent == nil || parent.ProjectID != projectID {
+			return fmt.Errorf("%w: parent node not found", ErrInvalidMove)
+		}
+
+		edges, err := repos.Edges.ListByProjectID(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("failed to list edges: %w", err)
+		}
+		parentByChild := make(map[uuid.UUID]*uuid.UUID, len(edges))
+		for _, edge := range edges {
+			parentByChild[edge.ChildNodeID] = edge.ParentNodeID
+		}
+		if parentByChild[nodeID] == nil {
+			return fmt.Errorf("%w: root node cannot be moved", ErrInvalidMove)
+		}
+		// 自分自身や子孫の下へは移動できない
+		if createsCycle(parentByChild, nodeID, req.ParentNodeID) {
+			return fmt.Errorf("%w: cannot move a node under its own descendant", ErrInvalidMove)
+		}
+		oldSiblings := []uuid.UUID{}
+		for _, edge := range edges {
+			if sameNodeID(edge.ParentNodeID, parentByChild[nodeID]) {
+				oldSiblings = append(oldSiblings, edge.ChildNodeID)
+			}
+		}
+
+		orderIndex := -1
+		if req.OrderIndex != nil {
+			orderIndex = *req.OrderIndex
+		}
+		if err := repos.Edges.Move(ctx, projectID, nodeID, &req.ParentNodeID, orderIndex); err != nil {
+			return err
+		}
+
+		// 移動元と移動先の兄弟の並びを通知する
+		oldParentNodeID := parentByChild[nodeID]
+		edges, err = repos.Edges.ListByProjectID(ctx, projectID)
+		if err != nil {
+			return fmt.Errorf("failed to list edges: %w", err)
+		}
+		moved = model.NodeMovedEvent{NodeID: nodeID, ParentNodeID: req.ParentNodeID, Edges: []model.Edge{}}
+		newSiblings := []uuid.UUID{}
+		for _, edge := range edges {
+			if sameNodeID(edge.ParentNodeID, oldParentNodeID) || sameNodeID(edge.ParentNodeID, &req.ParentNodeID) {
+				moved.Edges = append(moved.Edges, edge)
+			}
+			if sameNodeID(edge.ParentNodeID, &req.ParentNodeID) {
+				newSiblings = append(newSiblings, edge.ChildNodeID)
+			}
+		}
+
+		before := model.OperationValue{ParentNodeID: oldParentNodeID, OrderedChildNodeIDs: oldSiblings}
+		after := model.OperationValue{ParentNodeID: &req.ParentNodeID, OrderedChildNodeIDs: newSiblings}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeMove, []uuid.UUID{nodeID}, before, after); err != nil {
+			return err
+		}
+
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeMoved, moved)
+	return nil
 }
 
-func (s *NodeService) DeleteNode(ctx context.Context, projectID, nodeID uuid.UUID) error {
-	return s.nodeRepo.SoftDeleteWithDescendants(ctx, projectID, nodeID)
+// questionContext は質問生成の材料となる親・祖先・兄弟ノードです
+type questionContext struct {
+	parent      model.Node
+	ancestors   []model.Node
+	siblings    []model.Node
+	edgeByChild map[uuid.UUID]model.Edge
 }
 
-func (s *NodeService) generateQuestion(ctx context.Context, projectID uuid.UUID, parentNodeID uuid.UUID) (string, error) {
+func (s *NodeService) loadQuestionContext(ctx context.Context, projectID uuid.UUID, parentNodeID uuid.UUID) (*questionContext, error) {
 	nodes, err := s.nodeRepo.ListByProjectID(ctx, projectID)
 	if err != nil {
-		return "", fmt.Errorf("failed to list nodes: %w", err)
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
 	}
 	edges, err := s.edgeRepo.ListByProjectID(ctx, projectID)
 	if err != nil {
-		return "", fmt.Errorf("failed to list edges: %w", err)
+		return nil, fmt.Errorf("failed to list edges: %w", err)
 	}
 
 	nodeByID := make(map[uuid.UUID]model.Node, len(nodes))
@@ -99,7 +324,7 @@ func (s *NodeService) generateQuestion(ctx context.Context, projectID uuid.UUID,
 	}
 	parentNode, ok := nodeByID[parentNodeID]
 	if !ok {
-		return "", fmt.Errorf("parent node not found")
+		return nil, fmt.Errorf("parent node not found")
 	}
 
 	parentByChild := make(map[uuid.UUID]*uuid.UUID, len(edges))
@@ -107,13 +332,26 @@ func (s *NodeService) generateQuestion(ctx context.Context, projectID uuid.UUID,
 		parentByChild[edge.ChildNodeID] = edge.ParentNodeID
 	}
 
-	ancestors := collectAncestors(parentNodeID, parentByChild, nodeByID)
-	siblings := collectSiblings(parentNodeID, edges, nodeByID)
-
 	edgeByChild := make(map[uuid.UUID]model.Edge, len(edges))
 	for _, edge := range edges {
 		edgeByChild[edge.ChildNodeID] = edge
 	}
+
+	return &questionContext{
+		parent:      parentNode,
+		ancestors:   collectAncestors(parentNodeID, parentByChild, nodeByID),
+		siblings:    collectSiblings(parentNodeID, edges, nodeByID),
+		edgeByChild: edgeByChild,
+	}, nil
+}
+
+func (s *NodeService) generateQuestion(ctx context.Context, projectID uuid.UUID, parentNodeID uuid.UUID) (string, error) {
+	qc, err := s.loadQuestionContext(ctx, projectID, parentNodeID)
+	if err != nil {
+		return "", err
+	}
+	parentNode, ancestors, siblings, edgeByChild := qc.parent, qc.ancestors, qc.siblings, qc.edgeByChild
+
 	prompt := buildQuestionPrompt(parentNode, ancestors, siblings, edgeByChild)
 	if s.questionGenerator == nil {
 		return fallbackQuestionText(&parentNode, ancestors, siblings), nil
@@ -472,6 +710,30 @@ func normalizePlainText(text string) string {
 
 func fallbackCandidatesByFocus(focus string) []string {
 	switch focus {
+	case string(model.RelationWhy):
+		return []string{
+			"なぜそれを目指す？",
+			"それで何が得られる？",
+			"本当の目的は何ですか？",
+		}
+	case string(model.RelationHow):
+		return []string{
+			"どうやって進めますか？",
+			"どんな方法がありますか？",
+			"最初の一歩は？",
+		}
+	case string(model.RelationWhat):
+		return []string{
+			"何ができたら完了？",
+			"必要なものは何ですか？",
+			"何から手をつけますか？",
+		}
+	case string(model.RelationConcrete):
+		return []string{
+			"具体的には何をしますか？",
+			"例えばどんな場面ですか？",
+			"いつ・どこでやりますか？",
+		}
 	case "purpose":
 		return []string{
 			"この目標の目的は？",
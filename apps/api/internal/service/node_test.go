@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// moveTestTree は root の下に a と b、a の下に a1、a1 の下に a1x を持つツリーです
+func moveTestTree() (projectID uuid.UUID, ids map[string]uuid.UUID, nodes []model.Node, edges []model.Edge) {
+	projectID = uuid.New()
+	ids = map[string]uuid.UUID{}
+	parents := []struct{ name, parent string }{
+		{"root", ""}, {"a", "root"}, {"b", "root"}, {"a1", "a"}, {"a1x", "a1"},
+	}
+	for i, entry := range parents {
+		ids[entry.name] = uuid.New()
+		nodes = append(nodes, model.Node{ID: ids[entry.name], ProjectID: projectID, Content: entry.name})
+		edge := model.Edge{ID: uuid.New(), ProjectID: projectID, ChildNodeID: ids[entry.name], Relation: model.RelationNeutral, OrderIndex: i}
+		if entry.parent != "" {
+			parentID := ids[entry.parent]
+			edge.ParentNodeID = &parentID
+		}
+		edges = append(edges, edge)
+	}
+	return projectID, ids, nodes, edges
+}
+
+func TestCreatesCycle(t *testing.T) {
+	_, ids, _, edges := moveTestTree()
+	parentByChild := make(map[uuid.UUID]*uuid.UUID, len(edges))
+	for _, edge := range edges {
+		parentByChild[edge.ChildNodeID] = edge.ParentNodeID
+	}
+
+	tests := []struct {
+		name      string
+		node      string
+		newParent string
+		want      bool
+	}{
+		{name: "under itself", node: "a", newParent: "a", want: true},
+		{name: "under its child", node: "a", newParent: "a1", want: true},
+		{name: "under a deeper descendant", node: "a", newParent: "a1x", want: true},
+		{name: "root under a descendant", node: "root", newParent: "b", want: true},
+		{name: "under an unrelated branch", node: "a", newParent: "b", want: false},
+		{name: "under its own parent", node: "a1", newParent: "a", want: false},
+		{name: "leaf under the root", node: "a1x", newParent: "root", want: false},
+		{name: "under a sibling's descendant", node: "b", newParent: "a1x", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := createsCycle(parentByChild, ids[tt.node], ids[tt.newParent]); got != tt.want {
+				t.Errorf("createsCycle(%s -> %s) = %v, want %v", tt.node, tt.newParent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveNode(t *testing.T) {
+	tests := []struct {
+		name      string
+		node      string
+		newParent string
+		wantErr   error
+		// wantChildren は移動後の新しい親の子の並びです
+		wantChildren []string
+	}{
+		{name: "under itself", node: "a", newParent: "a", wantErr: ErrInvalidMove},
+		{name: "under a descendant", node: "a", newParent: "a1x", wantErr: ErrInvalidMove},
+		{name: "root node", node: "root", newParent: "b", wantErr: ErrInvalidMove},
+		{name: "under an unrelated branch", node: "a1", newParent: "b", wantChildren: []string{"a1"}},
+		{name: "to the end of the grandparent", node: "a1x", newParent: "root", wantChildren: []string{"a", "b", "a1x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectID, ids, nodes, edges := moveTestTree()
+			edgeRepo := &stubEdgeRepo{edges: append([]model.Edge{}, edges...)}
+			operations := &memoryOperationRepo{}
+			repos := repository.Repositories{
+				Projects:   &stubProjectRepo{revision: 1},
+				Nodes:      &stubNodeRepo{nodes: nodes},
+				Edges:      edgeRepo,
+				Operations: operations,
+			}
+			svc := NewNodeService(repos.Nodes, repos.Edges, nil, nil, stubUnitOfWork{repos: repos})
+
+			err := svc.MoveNode(context.Background(), uuid.New(), projectID, ids[tt.node], model.MoveNodeRequest{ParentNodeID: ids[tt.newParent]})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("MoveNode() error = %v, want %v", err, tt.wantErr)
+				}
+				if !reflect.DeepEqual(edgeRepo.edges, edges) || len(operations.ops) != 0 {
+					t.Errorf("rejected move changed the tree or the history")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MoveNode() error = %v", err)
+			}
+
+			parentID := ids[tt.newParent]
+			children, _ := childOrder(context.Background(), repos, projectID, &parentID)
+			names := make(map[uuid.UUID]string, len(ids))
+			for name, id := range ids {
+				names[id] = name
+			}
+			var got []string
+			for _, id := range children {
+				got = append(got, names[id])
+			}
+			if !reflect.DeepEqual(got, tt.wantChildren) {
+				t.Errorf("children of %s = %v, want %v", tt.newParent, got, tt.wantChildren)
+			}
+			if len(operations.ops) != 1 || operations.ops[0].Kind != model.OperationNodeMove {
+				t.Errorf("operations = %+v, want one node.move", operations.ops)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// recordOperation は変更と同じトランザクションで操作履歴に追記します
+// 新しい操作を記録すると、そのユーザーが取り消した操作はやり直せなくなります
+func recordOperation(ctx context.Context, repos repository.Repositories, projectID, userID uuid.UUID, kind model.OperationKind, nodeIDs []uuid.UUID, before, after model.OperationValue) error {
+	if err := repos.Operations.DiscardUndone(ctx, projectID, userID); err != nil {
+		return err
+	}
+	return repos.Operations.Append(ctx, model.Operation{
+		ProjectID: projectID,
+		UserID:    userID,
+		Kind:      kind,
+		NodeIDs:   nodeIDs,
+		Before:    before,
+		After:     after,
+	})
+}
+
+// childOrder は parentNodeID の下にある削除されていない子ノードを並び順どおりに返します
+func childOrder(ctx context.Context, repos repository.Repositories, projectID uuid.UUID, parentNodeID *uuid.UUID) ([]uuid.UUID, error) {
+	edges, err := repos.Edges.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list edges: %w", err)
+	}
+	ordered := []uuid.UUID{}
+	for _, edge := range edges {
+		if sameNodeID(edge.ParentNodeID, parentNodeID) {
+			ordered = append(ordered, edge.ChildNodeID)
+		}
+	}
+	return ordered, nil
+}
+
+// restoreOrder は子ノードを記録した並び順に戻します
+// 記録後に追加された子は元の相対順のまま末尾に、削除された子は無視します
+func restoreOrder(ctx context.Context, repos repository.Repositories, projectID uuid.UUID, parentNodeID *uuid.UUID, recorded []uuid.UUID) error {
+	if len(recorded) == 0 {
+		return nil
+	}
+	current, err := childOrder(ctx, repos, projectID, parentNodeID)
+	if err != nil {
+		return err
+	}
+	return repos.Edges.Reorder(ctx, projectID, parentNodeID, mergeOrder(recorded, current))
+}
+
+// mergeOrder は記録した並び順 recorded を現在の子ノード current に当てはめた並び順を返します
+func mergeOrder(recorded, current []uuid.UUID) []uuid.UUID {
+	live := make(map[uuid.UUID]struct{}, len(current))
+	for _, id := range current {
+		live[id] = struct{}{}
+	}
+	ordered := make([]uuid.UUID, 0, len(current))
+	placed := make(map[uuid.UUID]struct{}, len(current))
+	for _, id := range recorded {
+		if _, ok := live[id]; !ok {
+			continue
+		}
+		if _, ok := placed[id]; ok {
+			continue
+		}
+		placed[id] = struct{}{}
+		ordered = append(ordered, id)
+	}
+	for _, id := range current {
+		if _, ok := placed[id]; !ok {
+			ordered = append(ordered, id)
+		}
+	}
+	return ordered
+}
+
+// createsCycle は nodeID を newParentNodeID の下へ移動すると循環するか（自分自身や子孫の下への移動か）を判定します
+func createsCycle(parentByChild map[uuid.UUID]*uuid.UUID, nodeID, newParentNodeID uuid.UUID) bool {
+	visited := make(map[uuid.UUID]struct{}, len(parentByChild))
+	for current := &newParentNodeID; current != nil; current = parentByChild[*current] {
+		if _, ok := visited[*current]; ok {
+			break
+		}
+		visited[*current] = struct{}{}
+		if *current == nodeID {
+			return true
+		}
+	}
+	return false
+}
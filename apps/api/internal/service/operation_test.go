@@ -0,0 +1,67 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestMergeOrder(t *testing.T) {
+	a, b, c, d, e := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	names := map[uuid.UUID]string{a: "a", b: "b", c: "c", d: "d", e: "e"}
+
+	tests := []struct {
+		name     string
+		recorded []uuid.UUID
+		current  []uuid.UUID
+		want     []uuid.UUID
+	}{
+		{name: "same children", recorded: []uuid.UUID{c, a, b}, current: []uuid.UUID{a, b, c}, want: []uuid.UUID{c, a, b}},
+		{name: "new children follow in their current order", recorded: []uuid.UUID{b, a}, current: []uuid.UUID{d, a, e, b}, want: []uuid.UUID{b, a, d, e}},
+		{name: "deleted children are dropped", recorded: []uuid.UUID{c, b, a}, current: []uuid.UUID{a, c}, want: []uuid.UUID{c, a}},
+		{name: "added and deleted together", recorded: []uuid.UUID{c, b, a}, current: []uuid.UUID{e, a, c, d}, want: []uuid.UUID{c, a, e, d}},
+		{name: "duplicated ids are placed once", recorded: []uuid.UUID{b, a, b}, current: []uuid.UUID{a, b}, want: []uuid.UUID{b, a}},
+		{name: "no children left", recorded: []uuid.UUID{a, b}, current: []uuid.UUID{}, want: []uuid.UUID{}},
+	}
+
+	label := func(ids []uuid.UUID) []string {
+		out := make([]string, len(ids))
+		for i, id := range ids {
+			out[i] = names[id]
+		}
+		return out
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeOrder(tt.recorded, tt.current); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeOrder() = %v, want %v", label(got), label(tt.want))
+			}
+		})
+	}
+}
+
+// TestCreatesCycleTerminates は親をたどれない場合や、壊れたデータで親子関係が既に循環している場合でも判定が止まることを確かめます
+func TestCreatesCycleTerminates(t *testing.T) {
+	root, a, loopX, loopY := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	parentByChild := map[uuid.UUID]*uuid.UUID{root: nil, a: &root, loopX: &loopY, loopY: &loopX}
+
+	tests := []struct {
+		name      string
+		nodeID    uuid.UUID
+		newParent uuid.UUID
+		want      bool
+	}{
+		{name: "under an unknown node", nodeID: a, newParent: uuid.New(), want: false},
+		{name: "existing loop without the node", nodeID: a, newParent: loopX, want: false},
+		{name: "existing loop with the node", nodeID: loopY, newParent: loopX, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := createsCycle(parentByChild, tt.nodeID, tt.newParent); got != tt.want {
+				t.Errorf("createsCycle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// applyProgress はツリーの各ノードに、子孫から積み上げた達成率を設定します
+//   - done のノードは 1、子を持たない（dropped 以外の子がない）ノードは done なら 1、それ以外は 0
+//   - それ以外は dropped を除いた子の達成率を weight（未設定は1）で加重平均します
+func applyProgress(tree *model.TreeResponse) {
+	index := make(map[uuid.UUID]int, len(tree.Nodes))
+	for i := range tree.Nodes {
+		index[tree.Nodes[i].ID] = i
+	}
+	children := make(map[uuid.UUID][]int)
+	for _, edge := range tree.Edges {
+		if edge.ParentNodeID == nil {
+			continue
+		}
+		if i, ok := index[edge.ChildNodeID]; ok {
+			children[*edge.ParentNodeID] = append(children[*edge.ParentNodeID], i)
+		}
+	}
+
+	progress := make([]*float64, len(tree.Nodes))
+	var compute func(i int) float64
+	compute = func(i int) float64 {
+		if progress[i] != nil {
+			return *progress[i]
+		}
+		node := &tree.Nodes[i]
+
+		var sum, total float64
+		for _, c := range children[node.ID] {
+			p := compute(c)
+			if tree.Nodes[c].Status == model.NodeStatusDropped {
+				continue
+			}
+			weight := 1.0
+			if tree.Nodes[c].Weight != nil {
+				weight = *tree.Nodes[c].Weight
+			}
+			sum += p * weight
+			total += weight
+		}
+
+		var value float64
+		switch {
+		case node.Status == model.NodeStatusDone:
+			value = 1
+		case total > 0:
+			value = sum / total
+		}
+		progress[i] = &value
+		return value
+	}
+
+	for i := range tree.Nodes {
+		compute(i)
+		tree.Nodes[i].Progress = progress[i]
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// progressNode は applyProgress のテスト用のノードです（parent が空なら根）
+type progressNode struct {
+	name   string
+	parent string
+	status model.NodeStatus
+	weight float64
+}
+
+func buildProgressTree(specs []progressNode) (*model.TreeResponse, map[string]int) {
+	tree := &model.TreeResponse{}
+	ids := make(map[string]uuid.UUID, len(specs))
+	index := make(map[string]int, len(specs))
+	for i, spec := range specs {
+		ids[spec.name] = uuid.New()
+		index[spec.name] = i
+		node := model.Node{ID: ids[spec.name], Content: spec.name, Status: spec.status}
+		if spec.weight != 0 {
+			weight := spec.weight
+			node.Weight = &weight
+		}
+		tree.Nodes = append(tree.Nodes, node)
+	}
+	for i, spec := range specs {
+		edge := model.Edge{ChildNodeID: ids[spec.name], Relation: model.RelationNeutral, OrderIndex: i}
+		if spec.parent != "" {
+			parentID := ids[spec.parent]
+			edge.ParentNodeID = &parentID
+		}
+		tree.Edges = append(tree.Edges, edge)
+	}
+	return tree, index
+}
+
+func TestApplyProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		nodes []progressNode
+		want  map[string]float64
+	}{
+		{
+			name:  "todo leaf",
+			nodes: []progressNode{{name: "root", status: model.NodeStatusTodo}},
+			want:  map[string]float64{"root": 0},
+		},
+		{
+			name:  "done leaf",
+			nodes: []progressNode{{name: "root", status: model.NodeStatusDone}},
+			want:  map[string]float64{"root": 1},
+		},
+		{
+			name: "unweighted average",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusTodo},
+				{name: "a", parent: "root", status: model.NodeStatusDone},
+				{name: "b", parent: "root", status: model.NodeStatusDoing},
+			},
+			want: map[string]float64{"root": 0.5, "a": 1, "b": 0},
+		},
+		{
+			name: "weighted average",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusTodo},
+				{name: "a", parent: "root", status: model.NodeStatusDone, weight: 3},
+				{name: "b", parent: "root", status: model.NodeStatusTodo},
+			},
+			want: map[string]float64{"root": 0.75},
+		},
+		{
+			name: "dropped children are ignored",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusTodo},
+				{name: "a", parent: "root", status: model.NodeStatusDone},
+				{name: "b", parent: "root", status: model.NodeStatusDropped, weight: 10},
+			},
+			want: map[string]float64{"root": 1, "b": 0},
+		},
+		{
+			name: "only dropped children",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusTodo},
+				{name: "a", parent: "root", status: model.NodeStatusDropped},
+			},
+			want: map[string]float64{"root": 0},
+		},
+		{
+			name: "done parent overrides children",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusDone},
+				{name: "a", parent: "root", status: model.NodeStatusTodo},
+			},
+			want: map[string]float64{"root": 1, "a": 0},
+		},
+		{
+			name: "nested roll-up",
+			nodes: []progressNode{
+				{name: "root", status: model.NodeStatusTodo},
+				{name: "a", parent: "root", status: model.NodeStatusDone},
+				{name: "b", parent: "root", status: model.NodeStatusDoing},
+				{name: "b1", parent: "b", status: model.NodeStatusDone},
+				{name: "b2", parent: "b", status: model.NodeStatusTodo},
+				{name: "b3", parent: "b", status: model.NodeStatusDone, weight: 2},
+			},
+			want: map[string]float64{"root": 0.875, "b": 0.75},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, index := buildProgressTree(tt.nodes)
+			applyProgress(tree)
+
+			for _, node := range tree.Nodes {
+				if node.Progress == nil {
+					t.Fatalf("%s: Progress is nil", node.Content)
+				}
+			}
+			for name, want := range tt.want {
+				if got := *tree.Nodes[index[name]].Progress; math.Abs(got-want) > 1e-9 {
+					t.Errorf("%s: Progress = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
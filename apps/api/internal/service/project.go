@@ -5,42 +5,61 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/export"
 	"github.com/mokuhyo-driven-test/api/internal/model"
 	"github.com/mokuhyo-driven-test/api/internal/repository"
 )
 
 type ProjectService struct {
 	projectRepo repository.ProjectRepository
+	memberRepo  repository.MemberRepository
 	nodeRepo    repository.NodeRepository
 	edgeRepo    repository.EdgeRepository
+	uow         repository.UnitOfWork
+	broker      *events.Broker
 }
 
-func NewProjectService(projectRepo repository.ProjectRepository, nodeRepo repository.NodeRepository, edgeRepo repository.EdgeRepository) *ProjectService {
+func NewProjectService(projectRepo repository.ProjectRepository, memberRepo repository.MemberRepository, nodeRepo repository.NodeRepository, edgeRepo repository.EdgeRepository, uow repository.UnitOfWork, broker *events.Broker) *ProjectService {
 	return &ProjectService{
 		projectRepo: projectRepo,
+		memberRepo:  memberRepo,
 		nodeRepo:    nodeRepo,
 		edgeRepo:    edgeRepo,
+		uow:         uow,
+		broker:      broker,
 	}
 }
 
 func (s *ProjectService) CreateProject(ctx context.Context, userID uuid.UUID, req model.CreateProjectRequest) (*model.Project, error) {
-	project, err := s.projectRepo.Create(ctx, userID, req)
+	var project *model.Project
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		project, err = repos.Projects.Create(ctx, userID, req)
+		if err != nil {
+			return err
+		}
+
+		// Create initial root node
+		initialNode, err := repos.Nodes.Create(ctx, project.ID, req.Title, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create initial node: %w", err)
+		}
+
+		// Create edge for root node
+		_, err = repos.Edges.Create(ctx, project.ID, nil, initialNode.ID, model.RelationNeutral, nil, 0)
+		if err != nil {
+			return fmt.Errorf("failed to create initial edge: %w", err)
+		}
+
+		// ノードとエッジの作成で進んだリビジョンを返す
+		project, err = repos.Projects.GetByID(ctx, project.ID)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// Create initial root node
-	initialNode, err := s.nodeRepo.Create(ctx, project.ID, req.Title, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create initial node: %w", err)
-	}
-
-	// Create edge for root node
-	_, err = s.edgeRepo.Create(ctx, project.ID, nil, initialNode.ID, model.RelationNeutral, nil, 0)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create initial edge: %w", err)
-	}
-
 	return project, nil
 }
 
@@ -52,20 +71,101 @@ func (s *ProjectService) GetProject(ctx context.Context, projectID uuid.UUID) (*
 	return s.projectRepo.GetByID(ctx, projectID)
 }
 
-func (s *ProjectService) UpdateProject(ctx context.Context, projectID uuid.UUID, req model.UpdateProjectRequest) error {
-	return s.projectRepo.Update(ctx, projectID, req)
-}
+// UpdateProject はプロジェクトを更新し、更新後のリビジョンを返します
+func (s *ProjectService) UpdateProject(ctx context.Context, projectID uuid.UUID, req model.UpdateProjectRequest, expectedRevision *int64) (int64, error) {
+	var project *model.Project
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, expectedRevision); err != nil {
+			return err
+		}
+		if err := repos.Projects.Update(ctx, projectID, req); err != nil {
+			return err
+		}
+		var err error
+		project, err = repos.Projects.GetByID(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		if project == nil {
+			return ErrProjectNotFound
+		}
+		revision = project.Revision
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
 
-func (s *ProjectService) CheckOwnership(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
-	return s.projectRepo.CheckOwnership(ctx, projectID, userID)
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeProjectUpdated, model.ProjectEvent{Project: *project})
+	return revision, nil
 }
 
-func (s *ProjectService) SaveProject(ctx context.Context, projectID uuid.UUID) error {
-	return s.projectRepo.UpdateUpdatedAt(ctx, projectID)
+// CheckPermission はユーザーの役割がプロジェクトに対する操作を許可するかを返します（メンバーでなければ false）
+func (s *ProjectService) CheckPermission(ctx context.Context, projectID, userID uuid.UUID, permission model.Permission) (bool, error) {
+	role, err := s.memberRepo.GetRole(ctx, projectID, userID)
+	if err != nil {
+		return false, err
+	}
+	return role != nil && role.Allows(permission), nil
 }
 
+// GetTree はツリーを読み込み、各ノードの達成率とタグを設定して返します
+// リビジョン（ETag）と内容が食い違わないよう、1つのスナップショットから読み込みます
 func (s *ProjectService) GetTree(ctx context.Context, projectID uuid.UUID) (*model.TreeResponse, error) {
-	project, err := s.projectRepo.GetByID(ctx, projectID)
+	var tree *model.TreeResponse
+	err := s.uow.Read(ctx, func(repos repository.Repositories) error {
+		var err error
+		if tree, err = loadTree(ctx, repos, projectID); err != nil {
+			return err
+		}
+		return applyTags(ctx, repos, tree)
+	})
+	if err != nil {
+		return nil, err
+	}
+	applyProgress(tree)
+	return tree, nil
+}
+
+// GetTreeByTags は指定したタグ（IDまたは名前）のいずれかが付いたノードと、それらを根までつなぐ祖先だけのツリーを返します
+// 達成率は絞り込む前のツリー全体で計算します
+func (s *ProjectService) GetTreeByTags(ctx context.Context, projectID uuid.UUID, tags []string) (*model.TreeResponse, error) {
+	tree, err := s.GetTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	filterTreeByTags(tree, tags)
+	return tree, nil
+}
+
+func applyTags(ctx context.Context, repos repository.Repositories, tree *model.TreeResponse) error {
+	tags, err := repos.Tags.ListByProjectID(ctx, tree.Project.ID)
+	if err != nil {
+		return err
+	}
+	nodeTags, err := repos.Tags.ListNodeTags(ctx, tree.Project.ID)
+	if err != nil {
+		return err
+	}
+
+	tagIDs := make(map[uuid.UUID][]uuid.UUID)
+	for _, nt := range nodeTags {
+		tagIDs[nt.NodeID] = append(tagIDs[nt.NodeID], nt.TagID)
+	}
+	for i := range tree.Nodes {
+		tree.Nodes[i].TagIDs = tagIDs[tree.Nodes[i].ID]
+	}
+	if tags == nil {
+		tags = []model.Tag{}
+	}
+	tree.Tags = tags
+	return nil
+}
+
+// loadTree は渡されたリポジトリ（トランザクション内のものを含む）からツリーを読み込みます
+func loadTree(ctx context.Context, repos repository.Repositories, projectID uuid.UUID) (*model.TreeResponse, error) {
+	project, err := repos.Projects.GetByID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,12 +173,12 @@ func (s *ProjectService) GetTree(ctx context.Context, projectID uuid.UUID) (*mod
 		return nil, fmt.Errorf("project not found")
 	}
 
-	nodes, err := s.nodeRepo.ListByProjectID(ctx, projectID)
+	nodes, err := repos.Nodes.ListByProjectID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
 
-	edges, err := s.edgeRepo.ListByProjectID(ctx, projectID)
+	edges, err := repos.Edges.ListByProjectID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
@@ -97,3 +197,12 @@ func (s *ProjectService) GetTree(ctx context.Context, projectID uuid.UUID) (*mod
 		Edges:   edges,
 	}, nil
 }
+
+// ExportProject はツリーを指定フォーマットのドキュメントとして出力します
+func (s *ProjectService) ExportProject(ctx context.Context, projectID uuid.UUID, format string) (*export.Document, error) {
+	tree, err := s.GetTree(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return export.Render(format, *tree)
+}
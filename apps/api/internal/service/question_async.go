@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+const asyncQuestionTimeout = 30 * time.Second
+
+// generateQuestionAsync は質問をバックグラウンドで生成して保存し、プロジェクトの購読者に通知します
+// 生成中の状態は CreateNode がノードに保存済みで、保存時に解除します
+func (s *NodeService) generateQuestionAsync(ctx context.Context, projectID, parentNodeID, nodeID uuid.UUID) {
+	// リクエストが終わっても生成を続けるため、キャンセルを引き継がない
+	bgCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), asyncQuestionTimeout)
+	go func() {
+		defer cancel()
+
+		question, err := s.generateQuestion(bgCtx, projectID, parentNodeID)
+		if err != nil {
+			question = fallbackQuestionText(nil, nil, nil)
+		}
+
+		// 質問の補完はユーザーの操作ではないため、操作履歴には記録しない
+		var node *model.Node
+		var revision int64
+		err = s.uow.Do(bgCtx, func(repos repository.Repositories) error {
+			if _, err := lockNode(bgCtx, repos, projectID, nodeID, nil); err != nil {
+				return err
+			}
+			if err := repos.Nodes.UpdateQuestion(bgCtx, nodeID, &question); err != nil {
+				return err
+			}
+			var err error
+			if node, err = repos.Nodes.GetByID(bgCtx, nodeID); err != nil {
+				return err
+			}
+			revision, err = currentRevision(bgCtx, repos, projectID)
+			return err
+		})
+		if errors.Is(err, ErrNodeNotFound) {
+			// 生成中に削除されたノードは通知せず、復元されたときに生成中のままにならないよう状態だけ解除する
+			if err := s.nodeRepo.SetQuestionStatus(bgCtx, nodeID, nil); err != nil {
+				log.Printf("failed to clear question status for node %s: %v", nodeID, err)
+			}
+			return
+		}
+		if err != nil {
+			log.Printf("failed to save generated question for node %s: %v", nodeID, err)
+			return
+		}
+
+		publishEvent(bgCtx, s.broker, projectID, revision, events.TypeQuestionReady,
+			model.QuestionReadyEvent{NodeID: nodeID, Question: question, Node: *node})
+	}()
+}
+
+// SubscribeEvents はプロジェクトのイベントを購読し、購読開始時点で質問を生成中のノードIDも返します
+// 購読してから読み込むため、その間に生成が終わったノードは question.ready で届きます
+func (s *NodeService) SubscribeEvents(ctx context.Context, projectID uuid.UUID) (<-chan events.Event, func(), []uuid.UUID, error) {
+	ch, unsubscribe := s.broker.Subscribe(projectID)
+	pendingNodeIDs, err := s.nodeRepo.ListQuestionPendingIDs(ctx, projectID)
+	if err != nil {
+		unsubscribe()
+		return nil, nil, nil, err
+	}
+	return ch, unsubscribe, pendingNodeIDs, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// suggestionRelations は質問候補を出す観点の順序です
+var suggestionRelations = []model.RelationType{
+	model.RelationWhy,
+	model.RelationHow,
+	model.RelationWhat,
+	model.RelationConcrete,
+}
+
+var relationFocusText = map[model.RelationType]string{
+	model.RelationWhy:      "目的や理由を掘り下げる",
+	model.RelationHow:      "進め方や手段を問う",
+	model.RelationWhat:     "達成の中身や必要なものを問う",
+	model.RelationConcrete: "具体的な行動や場面を問う",
+}
+
+// maxQuestionSuggestions は1回に返せる質問候補の上限です（観点の数 × 観点ごとの代替候補の数）
+const maxQuestionSuggestions = 12
+
+// SuggestQuestions は親ノードの下に子を作るための質問候補を最大 count 件（省略時は観点の数）返します
+// 観点を順に巡って1件ずつ候補を作り、count が観点の数より多い場合は2巡目以降で同じ観点の別の候補を加えます
+// 各候補は isQuestionUsable を満たし、兄弟ノードの質問とも候補同士とも重複しません
+// どの観点でも新しい候補を作れなくなった時点で打ち切るため、count 件に満たないことがあります
+func (s *NodeService) SuggestQuestions(ctx context.Context, projectID, parentNodeID uuid.UUID, count int) ([]model.QuestionSuggestion, error) {
+	if count <= 0 {
+		count = len(suggestionRelations)
+	}
+	if count > maxQuestionSuggestions {
+		return nil, fmt.Errorf("%w: count must be at most %d", ErrInvalidSuggestionCount, maxQuestionSuggestions)
+	}
+
+	parent, err := s.nodeRepo.GetByID(ctx, parentNodeID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil || parent.ProjectID != projectID {
+		return nil, ErrNodeNotFound
+	}
+
+	qc, err := s.loadQuestionContext(ctx, projectID, parentNodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := []model.QuestionSuggestion{}
+	for len(suggestions) < count {
+		added := false
+		for _, relation := range suggestionRelations {
+			if len(suggestions) >= count {
+				break
+			}
+			question, ok := s.suggestQuestion(ctx, qc, relation, suggestions)
+			if !ok {
+				continue
+			}
+			suggestions = append(suggestions, model.QuestionSuggestion{Question: question, Relation: relation})
+			added = true
+		}
+		if !added {
+			break
+		}
+	}
+	return suggestions, nil
+}
+
+func (s *NodeService) suggestQuestion(ctx context.Context, qc *questionContext, relation model.RelationType, chosen []model.QuestionSuggestion) (string, bool) {
+	// 既に選んだ候補も兄弟の質問と同じように重複判定の対象にする
+	taken := append([]model.Node{}, qc.siblings...)
+	for _, suggestion := range chosen {
+		question := suggestion.Question
+		taken = append(taken, model.Node{Question: &question})
+	}
+	usable := func(question string) bool {
+		return isQuestionUsable(question, qc.parent, qc.ancestors, taken)
+	}
+
+	if s.questionGenerator != nil {
+		prompt := buildSuggestionPrompt(qc, relation, chosen)
+		if raw, err := s.questionGenerator.GenerateQuestion(ctx, prompt); err == nil {
+			if question := normalizeQuestion(raw); usable(question) {
+				return question, true
+			}
+		}
+	}
+
+	for _, candidate := range fallbackCandidatesByFocus(string(relation)) {
+		if usable(candidate) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+func buildSuggestionPrompt(qc *questionContext, relation model.RelationType, chosen []model.QuestionSuggestion) string {
+	var builder strings.Builder
+	builder.WriteString(buildQuestionPrompt(qc.parent, qc.ancestors, qc.siblings, qc.edgeByChild))
+	builder.WriteString("\n")
+	builder.WriteString(fmt.Sprintf("観点: 「%s」（%s）の質問にしてください。\n", relation, relationFocusText[relation]))
+	if len(chosen) > 0 {
+		builder.WriteString("既に出した候補（これらと同じ質問は避ける）:\n")
+		for _, suggestion := range chosen {
+			builder.WriteString("- " + suggestion.Question + "\n")
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/ai"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// fixedQuestionGenerator は常に同じ質問を返します
+type fixedQuestionGenerator struct {
+	question string
+}
+
+func (g fixedQuestionGenerator) GenerateQuestion(ctx context.Context, prompt string) (string, error) {
+	return g.question, nil
+}
+
+func (g fixedQuestionGenerator) Close() error { return nil }
+
+// newQuestionTestService は「英語を話せるようになる」の下に、questions を質問に持つ子がある NodeService を作ります
+func newQuestionTestService(generator ai.QuestionGenerator, questions ...string) (*NodeService, uuid.UUID, uuid.UUID) {
+	projectID, parentID := uuid.New(), uuid.New()
+	nodes := []model.Node{{ID: parentID, ProjectID: projectID, Content: "英語を話せるようになる"}}
+	edges := []model.Edge{{ChildNodeID: parentID, Relation: model.RelationNeutral}}
+	for i, question := range questions {
+		child := model.Node{ID: uuid.New(), ProjectID: projectID, Content: "既存の子", Question: &question}
+		nodes = append(nodes, child)
+		edges = append(edges, model.Edge{ParentNodeID: &parentID, ChildNodeID: child.ID, Relation: model.RelationWhy, OrderIndex: i})
+	}
+	return NewNodeService(&stubNodeRepo{nodes: nodes}, &stubEdgeRepo{edges: edges}, generator, nil, nil), projectID, parentID
+}
+
+func TestSuggestQuestions(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator ai.QuestionGenerator
+		siblings  []string
+		count     int
+		// wantCount は返るべき件数、wantRelations は先頭から期待する観点の順です
+		wantCount     int
+		wantRelations []model.RelationType
+	}{
+		{
+			name:          "default count covers each relation once",
+			generator:     ai.NewFakeQuestionGenerator(),
+			wantCount:     4,
+			wantRelations: []model.RelationType{model.RelationWhy, model.RelationHow, model.RelationWhat, model.RelationConcrete},
+		},
+		{
+			name:          "fewer than relations",
+			generator:     ai.NewFakeQuestionGenerator(),
+			count:         2,
+			wantCount:     2,
+			wantRelations: []model.RelationType{model.RelationWhy, model.RelationHow},
+		},
+		{
+			name:          "more than relations cycles through them again",
+			count:         6,
+			wantCount:     6,
+			wantRelations: []model.RelationType{model.RelationWhy, model.RelationHow, model.RelationWhat, model.RelationConcrete, model.RelationWhy, model.RelationHow},
+		},
+		{
+			name:      "maximum with fake generator",
+			generator: ai.NewFakeQuestionGenerator(),
+			count:     maxQuestionSuggestions,
+			wantCount: maxQuestionSuggestions,
+		},
+		{
+			name:      "generator repeating itself falls back",
+			generator: fixedQuestionGenerator{question: "それはなぜ大切ですか？"},
+			count:     maxQuestionSuggestions,
+			wantCount: maxQuestionSuggestions,
+		},
+		{
+			name:      "sibling questions are not suggested again",
+			siblings:  []string{"なぜそれを目指す？", "それで何が得られる？", "どうやって進めますか？"},
+			count:     maxQuestionSuggestions,
+			wantCount: maxQuestionSuggestions - 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, projectID, parentID := newQuestionTestService(tt.generator, tt.siblings...)
+			suggestions, err := svc.SuggestQuestions(context.Background(), projectID, parentID, tt.count)
+			if err != nil {
+				t.Fatalf("SuggestQuestions() error = %v", err)
+			}
+			if len(suggestions) != tt.wantCount {
+				t.Errorf("got %d suggestions, want %d: %v", len(suggestions), tt.wantCount, suggestions)
+			}
+
+			seen := make(map[string]bool)
+			for _, question := range tt.siblings {
+				seen[question] = true
+			}
+			for i, suggestion := range suggestions {
+				if seen[suggestion.Question] {
+					t.Errorf("suggestion %q duplicates a sibling or an earlier candidate", suggestion.Question)
+				}
+				seen[suggestion.Question] = true
+				if !isQuestionUsable(suggestion.Question, model.Node{Content: "英語を話せるようになる"}, nil, nil) {
+					t.Errorf("suggestion %q is not usable", suggestion.Question)
+				}
+				if i < len(tt.wantRelations) && suggestion.Relation != tt.wantRelations[i] {
+					t.Errorf("suggestions[%d].Relation = %q, want %q", i, suggestion.Relation, tt.wantRelations[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestQuestionsErrors(t *testing.T) {
+	svc, projectID, parentID := newQuestionTestService(nil)
+
+	tests := []struct {
+		name      string
+		projectID uuid.UUID
+		nodeID    uuid.UUID
+		count     int
+		wantErr   error
+	}{
+		{name: "count over limit", projectID: projectID, nodeID: parentID, count: maxQuestionSuggestions + 1, wantErr: ErrInvalidSuggestionCount},
+		{name: "unknown node", projectID: projectID, nodeID: uuid.New(), wantErr: ErrNodeNotFound},
+		{name: "node in another project", projectID: uuid.New(), nodeID: parentID, wantErr: ErrNodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SuggestQuestions(context.Background(), tt.projectID, tt.nodeID, tt.count); !errors.Is(err, tt.wantErr) {
+				t.Errorf("SuggestQuestions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
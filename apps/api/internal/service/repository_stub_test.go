@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// このファイルのスタブは、サービスのテストで使うメソッドだけをメモリ上で実装します
+// それ以外のメソッドを呼ぶと、埋め込んだ nil のインターフェースで panic します
+
+type stubNodeRepo struct {
+	repository.NodeRepository
+	nodes []model.Node
+}
+
+func (r *stubNodeRepo) GetByID(ctx context.Context, nodeID uuid.UUID) (*model.Node, error) {
+	for i := range r.nodes {
+		if r.nodes[i].ID == nodeID {
+			node := r.nodes[i]
+			return &node, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubNodeRepo) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Node, error) {
+	var nodes []model.Node
+	for _, node := range r.nodes {
+		if node.ProjectID == projectID {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// stubEdgeRepo は PostgreSQL 実装と同じく、エッジを order_index 順に返します
+type stubEdgeRepo struct {
+	repository.EdgeRepository
+	edges []model.Edge
+}
+
+func (r *stubEdgeRepo) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]model.Edge, error) {
+	edges := append([]model.Edge{}, r.edges...)
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].OrderIndex < edges[j].OrderIndex })
+	return edges, nil
+}
+
+func (r *stubEdgeRepo) Move(ctx context.Context, projectID, childNodeID uuid.UUID, newParentNodeID *uuid.UUID, orderIndex int) error {
+	siblings, _ := childOrder(ctx, repository.Repositories{Edges: r}, projectID, newParentNodeID)
+	ordered := make([]uuid.UUID, 0, len(siblings)+1)
+	for _, id := range siblings {
+		if id != childNodeID {
+			ordered = append(ordered, id)
+		}
+	}
+	if orderIndex < 0 || orderIndex > len(ordered) {
+		orderIndex = len(ordered)
+	}
+	ordered = append(ordered[:orderIndex], append([]uuid.UUID{childNodeID}, ordered[orderIndex:]...)...)
+	for i := range r.edges {
+		if r.edges[i].ChildNodeID == childNodeID {
+			r.edges[i].ParentNodeID = newParentNodeID
+		}
+	}
+	return r.Reorder(ctx, projectID, newParentNodeID, ordered)
+}
+
+func (r *stubEdgeRepo) Reorder(ctx context.Context, projectID uuid.UUID, parentNodeID *uuid.UUID, orderedChildNodeIDs []uuid.UUID) error {
+	for i, id := range orderedChildNodeIDs {
+		for j := range r.edges {
+			if r.edges[j].ChildNodeID == id && sameNodeID(r.edges[j].ParentNodeID, parentNodeID) {
+				r.edges[j].OrderIndex = i
+			}
+		}
+	}
+	return nil
+}
+
+type stubProjectRepo struct {
+	repository.ProjectRepository
+	revision int64
+}
+
+func (r *stubProjectRepo) GetRevisionForUpdate(ctx context.Context, projectID uuid.UUID) (*int64, error) {
+	revision := r.revision
+	return &revision, nil
+}
+
+// memoryOperationRepo は PostgreSQL 実装と同じく、seq の順序で done / undone の操作を探します
+type memoryOperationRepo struct {
+	ops []model.Operation
+}
+
+func (r *memoryOperationRepo) Append(ctx context.Context, op model.Operation) error {
+	op.ID = uuid.New()
+	op.Seq = int64(len(r.ops) + 1)
+	op.State = model.OperationDone
+	r.ops = append(r.ops, op)
+	return nil
+}
+
+func (r *memoryOperationRepo) DiscardUndone(ctx context.Context, projectID, userID uuid.UUID) error {
+	for i := range r.ops {
+		if r.ops[i].ProjectID == projectID && r.ops[i].UserID == userID && r.ops[i].State == model.OperationUndone {
+			r.ops[i].State = model.OperationDiscarded
+		}
+	}
+	return nil
+}
+
+func (r *memoryOperationRepo) GetLastDone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error) {
+	for i := len(r.ops) - 1; i >= 0; i-- {
+		if r.ops[i].ProjectID == projectID && r.ops[i].UserID == userID && r.ops[i].State == model.OperationDone {
+			op := r.ops[i]
+			return &op, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOperationRepo) GetFirstUndone(ctx context.Context, projectID, userID uuid.UUID) (*model.Operation, error) {
+	for i := range r.ops {
+		if r.ops[i].ProjectID == projectID && r.ops[i].UserID == userID && r.ops[i].State == model.OperationUndone {
+			op := r.ops[i]
+			return &op, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryOperationRepo) SetState(ctx context.Context, operationID uuid.UUID, state model.OperationState) error {
+	for i := range r.ops {
+		if r.ops[i].ID == operationID {
+			r.ops[i].State = state
+		}
+	}
+	return nil
+}
+
+// stubUnitOfWork はトランザクションを使わず、同じリポジトリの組で fn を実行します
+type stubUnitOfWork struct {
+	repos repository.Repositories
+}
+
+func (u stubUnitOfWork) Do(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return fn(u.repos)
+}
+
+func (u stubUnitOfWork) Read(ctx context.Context, fn func(repos repository.Repositories) error) error {
+	return fn(u.repos)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type RevisionService struct {
+	nodeRepo     repository.NodeRepository
+	revisionRepo repository.NodeRevisionRepository
+	uow          repository.UnitOfWork
+	broker       *events.Broker
+}
+
+func NewRevisionService(nodeRepo repository.NodeRepository, revisionRepo repository.NodeRevisionRepository, uow repository.UnitOfWork, broker *events.Broker) *RevisionService {
+	return &RevisionService{nodeRepo: nodeRepo, revisionRepo: revisionRepo, uow: uow, broker: broker}
+}
+
+// GetHistory はノードの版を新しい順に、1つ前の版からの差分付きで返します
+func (s *RevisionService) GetHistory(ctx context.Context, projectID, nodeID uuid.UUID) (*model.NodeHistoryResponse, error) {
+	node, err := s.nodeRepo.GetByIDIncludingDeleted(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil || node.ProjectID != projectID {
+		return nil, ErrNodeNotFound
+	}
+
+	revisions, err := s.revisionRepo.ListByNodeID(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		var previousContent, previousQuestion string
+		if i+1 < len(revisions) {
+			previousContent = revisions[i+1].Content
+			previousQuestion = stringValue(revisions[i+1].Question)
+		}
+		revisions[i].ContentDiff = diffText(previousContent, revisions[i].Content)
+		revisions[i].QuestionDiff = diffText(previousQuestion, stringValue(revisions[i].Question))
+	}
+	if revisions == nil {
+		revisions = []model.NodeRevision{}
+	}
+	return &model.NodeHistoryResponse{NodeID: nodeID, Revisions: revisions}, nil
+}
+
+// RevertNode はノードの内容と質問を指定した版に戻します（戻した結果も新しい版として残ります）
+// expectedVersion が現在のバージョンと異なる場合は ErrVersionConflict を返します
+func (s *RevisionService) RevertNode(ctx context.Context, userID, projectID, nodeID, revisionID uuid.UUID, expectedVersion *int64) (*model.Node, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil || revision.NodeID != nodeID {
+		return nil, ErrRevisionNotFound
+	}
+
+	var node *model.Node
+	var projectRevision int64
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		current, err := lockNode(ctx, repos, projectID, nodeID, expectedVersion)
+		if err != nil {
+			return err
+		}
+		if err := repos.Nodes.UpdateText(ctx, nodeID, revision.Content, revision.Question); err != nil {
+			return err
+		}
+		before := model.OperationValue{Content: &current.Content, Question: current.Question}
+		after := model.OperationValue{Content: &revision.Content, Question: revision.Question}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeRevert, []uuid.UUID{nodeID}, before, after); err != nil {
+			return err
+		}
+		if node, err = repos.Nodes.GetByID(ctx, nodeID); err != nil {
+			return err
+		}
+		projectRevision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, projectRevision, events.TypeNodeUpdated, model.NodeEvent{Node: *node})
+	return node, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+const (
+	// maxSearchTerms は1回の検索で使う検索語の上限です
+	maxSearchTerms = 5
+	// snippetContext はスニペットで一致箇所の前に残す文字数です
+	snippetContext = 20
+	// snippetLength はスニペットの最大文字数です（省略記号を除く）
+	snippetLength = 80
+)
+
+type SearchService struct {
+	searchRepo repository.SearchRepository
+}
+
+func NewSearchService(searchRepo repository.SearchRepository) *SearchService {
+	return &SearchService{searchRepo: searchRepo}
+}
+
+// Search はユーザーがアクセスできるプロジェクトとノードを検索します
+// 空白（全角空白を含む）で区切った検索語すべてを部分一致で含むものを、プロジェクト、ノードの順に返します
+func (s *SearchService) Search(ctx context.Context, userID uuid.UUID, query string, limit int) (*model.SearchResponse, error) {
+	terms := strings.Fields(query)
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	response := &model.SearchResponse{Query: query, Hits: []model.SearchHit{}}
+	if len(terms) == 0 {
+		return response, nil
+	}
+
+	patterns := make([]string, len(terms))
+	for i, term := range terms {
+		patterns[i] = "%" + likeEscaper.Replace(term) + "%"
+	}
+	normalized := strings.Join(terms, " ")
+
+	projects, err := s.searchRepo.SearchProjects(ctx, userID, normalized, patterns, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range projects {
+		field, text := "title", c.ProjectTitle
+		if !containsAnyTerm(text, terms) && c.ProjectDescription != nil {
+			field, text = "description", *c.ProjectDescription
+		}
+		snippet, highlights := buildSnippet(text, terms)
+		response.Hits = append(response.Hits, model.SearchHit{
+			Kind:         model.SearchHitProject,
+			ProjectID:    c.ProjectID,
+			ProjectTitle: c.ProjectTitle,
+			Field:        field,
+			Snippet:      snippet,
+			Highlights:   highlights,
+			Score:        c.Score,
+		})
+	}
+
+	nodes, err := s.searchRepo.SearchNodes(ctx, userID, normalized, patterns, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range nodes {
+		field, text := "content", c.Content
+		if !containsAnyTerm(text, terms) && c.Question != nil {
+			field, text = "question", *c.Question
+		}
+		snippet, highlights := buildSnippet(text, terms)
+		response.Hits = append(response.Hits, model.SearchHit{
+			Kind:         model.SearchHitNode,
+			ProjectID:    c.ProjectID,
+			ProjectTitle: c.ProjectTitle,
+			NodeID:       c.NodeID,
+			Field:        field,
+			Snippet:      snippet,
+			Highlights:   highlights,
+			Breadcrumb:   c.Breadcrumb,
+			Score:        c.Score,
+		})
+	}
+
+	return response, nil
+}
+
+// likeEscaper は検索語を ILIKE のパターンにそのまま埋め込めるようにします
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func containsAnyTerm(text string, terms []string) bool {
+	folded := foldRunes(text)
+	for _, term := range terms {
+		if len(findRunes(folded, foldRunes(term))) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildSnippet は最初に一致した箇所の周辺を切り出し、スニペット内での一致範囲（文字単位、開始位置の順）を返します
+func buildSnippet(text string, terms []string) (string, []model.SearchHighlight) {
+	runes := []rune(text)
+	folded := foldRunes(text)
+
+	first := len(runes)
+	for _, term := range terms {
+		if matches := findRunes(folded, foldRunes(term)); len(matches) > 0 && matches[0] < first {
+			first = matches[0]
+		}
+	}
+	if first == len(runes) {
+		first = 0
+	}
+
+	start := first - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLength
+	if end > len(runes) {
+		end = len(runes)
+	}
+
+	prefix := ""
+	if start > 0 {
+		prefix = "…"
+	}
+	suffix := ""
+	if end < len(runes) {
+		suffix = "…"
+	}
+	offset := utf8.RuneCountInString(prefix) - start
+
+	var highlights []model.SearchHighlight
+	for _, term := range terms {
+		termRunes := foldRunes(term)
+		for _, pos := range findRunes(folded[start:end], termRunes) {
+			highlights = append(highlights, model.SearchHighlight{
+				Start: start + pos + offset,
+				End:   start + pos + len(termRunes) + offset,
+			})
+		}
+	}
+
+	return prefix + string(runes[start:end]) + suffix, mergeHighlights(highlights)
+}
+
+// mergeHighlights は検索語ごとに集めた一致範囲を開始位置の順に並べ、重なる・隣接する範囲を1つにまとめます
+// （「目標」と「目」のように一方が他方を含む検索語でも、同じ文字を二重に強調しないようにします）
+func mergeHighlights(highlights []model.SearchHighlight) []model.SearchHighlight {
+	sort.Slice(highlights, func(i, j int) bool {
+		if highlights[i].Start != highlights[j].Start {
+			return highlights[i].Start < highlights[j].Start
+		}
+		return highlights[i].End > highlights[j].End
+	})
+	merged := []model.SearchHighlight{}
+	for _, h := range highlights {
+		if n := len(merged); n > 0 && h.Start <= merged[n-1].End {
+			merged[n-1].End = max(merged[n-1].End, h.End)
+			continue
+		}
+		merged = append(merged, h)
+	}
+	return merged
+}
+
+// foldRunes は大文字・小文字を区別せずに比較するため、文字を小文字にそろえます
+func foldRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// findRunes は text 内で term が現れる位置（重ならないもの）を返します
+func findRunes(text, term []rune) []int {
+	if len(term) == 0 {
+		return nil
+	}
+	var positions []int
+	for i := 0; i+len(term) <= len(text); i++ {
+		if string(text[i:i+len(term)]) == string(term) {
+			positions = append(positions, i)
+			i += len(term) - 1
+		}
+	}
+	return positions
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestBuildSnippet(t *testing.T) {
+	type hl = model.SearchHighlight
+	long := strings.Repeat("あ", 30) + "目標" + strings.Repeat("い", 10)
+
+	tests := []struct {
+		name           string
+		text           string
+		terms          []string
+		wantSnippet    string
+		wantHighlights []hl
+	}{
+		{
+			name:           "match at start",
+			text:           "目標を達成する",
+			terms:          []string{"目標"},
+			wantSnippet:    "目標を達成する",
+			wantHighlights: []hl{{Start: 0, End: 2}},
+		},
+		{
+			name:           "no match",
+			text:           "目標を達成する",
+			terms:          []string{"習慣"},
+			wantSnippet:    "目標を達成する",
+			wantHighlights: []hl{},
+		},
+		{
+			name:           "case-insensitive and repeated",
+			text:           "Learn GO and go",
+			terms:          []string{"go"},
+			wantSnippet:    "Learn GO and go",
+			wantHighlights: []hl{{Start: 6, End: 8}, {Start: 13, End: 15}},
+		},
+		{
+			name:           "highlights are sorted across terms",
+			text:           "目標を達成する",
+			terms:          []string{"達成", "目標"},
+			wantSnippet:    "目標を達成する",
+			wantHighlights: []hl{{Start: 0, End: 2}, {Start: 3, End: 5}},
+		},
+		{
+			name:           "overlapping terms are merged",
+			text:           "目標を立てて目を向ける",
+			terms:          []string{"目標", "目"},
+			wantSnippet:    "目標を立てて目を向ける",
+			wantHighlights: []hl{{Start: 0, End: 2}, {Start: 6, End: 7}},
+		},
+		{
+			name:           "adjacent terms are merged",
+			text:           "目標達成の計画",
+			terms:          []string{"達成", "目標"},
+			wantSnippet:    "目標達成の計画",
+			wantHighlights: []hl{{Start: 0, End: 4}},
+		},
+		{
+			name:           "ellipsis prefix shifts offsets",
+			text:           long,
+			terms:          []string{"目標"},
+			wantSnippet:    "…" + strings.Repeat("あ", snippetContext) + "目標" + strings.Repeat("い", 10),
+			wantHighlights: []hl{{Start: snippetContext + 1, End: snippetContext + 3}},
+		},
+		{
+			name:           "ellipsis suffix when text is long",
+			text:           "目標" + strings.Repeat("う", 100),
+			terms:          []string{"目標"},
+			wantSnippet:    "目標" + strings.Repeat("う", snippetLength-2) + "…",
+			wantHighlights: []hl{{Start: 0, End: 2}},
+		},
+		{
+			name:           "match cut off by the end of the snippet is not highlighted",
+			text:           "目標" + strings.Repeat("う", snippetLength-3) + "目標",
+			terms:          []string{"目標"},
+			wantSnippet:    "目標" + strings.Repeat("う", snippetLength-3) + "目…",
+			wantHighlights: []hl{{Start: 0, End: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, highlights := buildSnippet(tt.text, tt.terms)
+			if snippet != tt.wantSnippet {
+				t.Errorf("snippet = %q, want %q", snippet, tt.wantSnippet)
+			}
+			if !reflect.DeepEqual(highlights, tt.wantHighlights) {
+				t.Errorf("highlights = %v, want %v", highlights, tt.wantHighlights)
+			}
+
+			// 範囲はスニペットの文字単位のオフセットで、一致した検索語を指している
+			runes := []rune(snippet)
+			for _, h := range highlights {
+				if h.Start < 0 || h.End > len(runes) || h.Start >= h.End {
+					t.Fatalf("highlight %v is out of range for %q", h, snippet)
+				}
+				if !containsAnyTerm(string(runes[h.Start:h.End]), tt.terms) {
+					t.Errorf("highlight %v = %q does not contain a term", h, string(runes[h.Start:h.End]))
+				}
+			}
+		})
+	}
+}
+
+func TestMergeHighlights(t *testing.T) {
+	type hl = model.SearchHighlight
+	tests := []struct {
+		name string
+		in   []hl
+		want []hl
+	}{
+		{name: "empty", in: nil, want: []hl{}},
+		{name: "disjoint unsorted", in: []hl{{Start: 5, End: 6}, {Start: 0, End: 2}}, want: []hl{{Start: 0, End: 2}, {Start: 5, End: 6}}},
+		{name: "contained", in: []hl{{Start: 2, End: 3}, {Start: 0, End: 4}}, want: []hl{{Start: 0, End: 4}}},
+		{name: "same start", in: []hl{{Start: 0, End: 1}, {Start: 0, End: 3}}, want: []hl{{Start: 0, End: 3}}},
+		{name: "partial overlap", in: []hl{{Start: 0, End: 3}, {Start: 2, End: 5}}, want: []hl{{Start: 0, End: 5}}},
+		{name: "adjacent", in: []hl{{Start: 3, End: 5}, {Start: 0, End: 3}}, want: []hl{{Start: 0, End: 5}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeHighlights(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeHighlights(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
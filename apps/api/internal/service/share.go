@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type ShareService struct {
+	shareRepo repository.ShareLinkRepository
+	uow       repository.UnitOfWork
+}
+
+func NewShareService(shareRepo repository.ShareLinkRepository, uow repository.UnitOfWork) *ShareService {
+	return &ShareService{
+		shareRepo: shareRepo,
+		uow:       uow,
+	}
+}
+
+// CreateShareLink は共有トークンを発行します。トークンはハッシュだけを保存するため、返せるのはこのときだけです
+func (s *ShareService) CreateShareLink(ctx context.Context, projectID, userID uuid.UUID, req model.CreateShareLinkRequest) (*model.CreateShareLinkResponse, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		t := time.Now().Add(time.Duration(*req.ExpiresInDays) * 24 * time.Hour)
+		expiresAt = &t
+	}
+
+	link, err := s.shareRepo.Create(ctx, projectID, userID, hashToken(token), req.Label, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &model.CreateShareLinkResponse{ShareLink: *link, Token: token}, nil
+}
+
+func (s *ShareService) ListShareLinks(ctx context.Context, projectID uuid.UUID) ([]model.ShareLink, error) {
+	links, err := s.shareRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if links == nil {
+		links = []model.ShareLink{}
+	}
+	return links, nil
+}
+
+func (s *ShareService) RevokeShareLink(ctx context.Context, projectID, shareLinkID uuid.UUID) error {
+	revoked, err := s.shareRepo.Revoke(ctx, projectID, shareLinkID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// GetSharedTree は有効な共有トークンに対応するツリーを、ユーザー識別子を除いて返します
+func (s *ShareService) GetSharedTree(ctx context.Context, token string) (*model.SharedTreeResponse, error) {
+	link, err := s.shareRepo.GetActiveByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, ErrShareLinkNotFound
+	}
+
+	// GetTree と同じく、プロジェクト・ノード・エッジを1つのスナップショットから読む
+	var tree *model.TreeResponse
+	err = s.uow.Read(ctx, func(repos repository.Repositories) error {
+		var err error
+		tree, err = loadTree(ctx, repos, link.ProjectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	applyProgress(tree)
+
+	return &model.SharedTreeResponse{
+		Project: model.SharedProject{
+			ID:          tree.Project.ID,
+			Title:       tree.Project.Title,
+			Description: tree.Project.Description,
+			Revision:    tree.Project.Revision,
+			CreatedAt:   tree.Project.CreatedAt,
+			UpdatedAt:   tree.Project.UpdatedAt,
+			ArchivedAt:  tree.Project.ArchivedAt,
+		},
+		Nodes: tree.Nodes,
+		Edges: tree.Edges,
+	}, nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type SnapshotService struct {
+	projectRepo  repository.ProjectRepository
+	snapshotRepo repository.SnapshotRepository
+	uow          repository.UnitOfWork
+	broker       *events.Broker
+}
+
+func NewSnapshotService(projectRepo repository.ProjectRepository, snapshotRepo repository.SnapshotRepository, uow repository.UnitOfWork, broker *events.Broker) *SnapshotService {
+	return &SnapshotService{
+		projectRepo:  projectRepo,
+		snapshotRepo: snapshotRepo,
+		uow:          uow,
+		broker:       broker,
+	}
+}
+
+// CreateSnapshot は現在のツリーを次のバージョンとして保存します
+func (s *SnapshotService) CreateSnapshot(ctx context.Context, projectID uuid.UUID) (*model.Snapshot, error) {
+	var snapshot *model.Snapshot
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		snapshot, err = createSnapshot(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func createSnapshot(ctx context.Context, repos repository.Repositories, projectID uuid.UUID) (*model.Snapshot, error) {
+	if err := repos.Projects.UpdateUpdatedAt(ctx, projectID); err != nil {
+		return nil, err
+	}
+	tree, err := loadTree(ctx, repos, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return repos.Snapshots.Create(ctx, projectID, *tree)
+}
+
+func (s *SnapshotService) ListSnapshots(ctx context.Context, projectID uuid.UUID) ([]model.Snapshot, error) {
+	snapshots, err := s.snapshotRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if snapshots == nil {
+		snapshots = []model.Snapshot{}
+	}
+	return snapshots, nil
+}
+
+func (s *SnapshotService) GetSnapshot(ctx context.Context, projectID uuid.UUID, version int) (*model.Snapshot, error) {
+	snapshot, err := s.snapshotRepo.GetByVersion(ctx, projectID, version)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, ErrSnapshotNotFound
+	}
+	return snapshot, nil
+}
+
+// DiffSnapshots は fromVersion から toVersion への変更点を返します
+func (s *SnapshotService) DiffSnapshots(ctx context.Context, projectID uuid.UUID, fromVersion, toVersion int) (*model.SnapshotDiff, error) {
+	from, err := s.GetSnapshot(ctx, projectID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetSnapshot(ctx, projectID, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffTrees(*from.Payload, *to.Payload)
+	diff.FromVersion = fromVersion
+	diff.ToVersion = toVersion
+	return diff, nil
+}
+
+// RestoreSnapshot はツリーを指定バージョンの状態に戻します
+// 復元前の状態は新しいバージョンとして保存されるため、復元自体も取り消せます
+func (s *SnapshotService) RestoreSnapshot(ctx context.Context, projectID uuid.UUID, version int) (*model.Snapshot, error) {
+	snapshot, err := s.GetSnapshot(ctx, projectID, version)
+	if err != nil {
+		return nil, err
+	}
+	// 現在の状態の保存と復元を同じトランザクションで行う
+	var revision int64
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if _, err := createSnapshot(ctx, repos, projectID); err != nil {
+			return fmt.Errorf("failed to save current state: %w", err)
+		}
+		if err := repos.Snapshots.RestoreTree(ctx, projectID, snapshot.Payload.Nodes, snapshot.Payload.Edges); err != nil {
+			return err
+		}
+		var err error
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeTreeRestored, model.TreeRestoredEvent{Version: snapshot.Version})
+	return snapshot, nil
+}
+
+func diffTrees(from, to model.TreeResponse) *model.SnapshotDiff {
+	diff := &model.SnapshotDiff{
+		AddedNodes:   []model.Node{},
+		RemovedNodes: []model.Node{},
+		ChangedNodes: []model.NodeDiff{},
+		ChangedEdges: []model.EdgeDiff{},
+	}
+
+	fromNodes := make(map[uuid.UUID]model.Node, len(from.Nodes))
+	for _, n := range from.Nodes {
+		fromNodes[n.ID] = n
+	}
+	toNodes := make(map[uuid.UUID]model.Node, len(to.Nodes))
+	for _, n := range to.Nodes {
+		toNodes[n.ID] = n
+	}
+	for _, n := range to.Nodes {
+		before, ok := fromNodes[n.ID]
+		if !ok {
+			diff.AddedNodes = append(diff.AddedNodes, n)
+			continue
+		}
+		if before.Content != n.Content || stringValue(before.Question) != stringValue(n.Question) {
+			diff.ChangedNodes = append(diff.ChangedNodes, model.NodeDiff{NodeID: n.ID, Before: before, After: n})
+		}
+	}
+	for _, n := range from.Nodes {
+		if _, ok := toNodes[n.ID]; !ok {
+			diff.RemovedNodes = append(diff.RemovedNodes, n)
+		}
+	}
+
+	fromEdges := make(map[uuid.UUID]model.Edge, len(from.Edges))
+	for _, e := range from.Edges {
+		fromEdges[e.ChildNodeID] = e
+	}
+	for _, e := range to.Edges {
+		before, ok := fromEdges[e.ChildNodeID]
+		if !ok {
+			continue
+		}
+		if !sameNodeID(before.ParentNodeID, e.ParentNodeID) ||
+			before.Relation != e.Relation ||
+			stringValue(before.RelationLabel) != stringValue(e.RelationLabel) ||
+			before.OrderIndex != e.OrderIndex {
+			diff.ChangedEdges = append(diff.ChangedEdges, model.EdgeDiff{ChildNodeID: e.ChildNodeID, Before: before, After: e})
+		}
+	}
+	return diff
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func sameNodeID(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestDiffTrees(t *testing.T) {
+	ids := map[string]uuid.UUID{}
+	for _, name := range []string{"root", "a", "b", "c", "d"} {
+		ids[name] = uuid.New()
+	}
+	node := func(name, content string, question *string) model.Node {
+		return model.Node{ID: ids[name], Content: content, Question: question, Status: model.NodeStatusTodo}
+	}
+	edge := func(child, parent string, relation model.RelationType, orderIndex int) model.Edge {
+		e := model.Edge{ChildNodeID: ids[child], Relation: relation, OrderIndex: orderIndex}
+		if parent != "" {
+			parentID := ids[parent]
+			e.ParentNodeID = &parentID
+		}
+		return e
+	}
+
+	// from は root の下に a, b, c が並ぶツリーです
+	from := model.TreeResponse{
+		Nodes: []model.Node{node("root", "root", nil), node("a", "a", nil), node("b", "b", strPtr("なぜ？")), node("c", "c", nil)},
+		Edges: []model.Edge{edge("root", "", model.RelationNeutral, 0), edge("a", "root", model.RelationWhy, 0), edge("b", "root", model.RelationHow, 1), edge("c", "root", model.RelationWhat, 2)},
+	}
+
+	tests := []struct {
+		name        string
+		to          model.TreeResponse
+		wantAdded   []string
+		wantRemoved []string
+		wantChanged []string
+		wantEdges   []string
+	}{
+		{
+			name: "identical",
+			to:   from,
+		},
+		{
+			name: "added node",
+			to: model.TreeResponse{
+				Nodes: append(append([]model.Node{}, from.Nodes...), node("d", "d", nil)),
+				Edges: append(append([]model.Edge{}, from.Edges...), edge("d", "a", model.RelationNeutral, 0)),
+			},
+			wantAdded: []string{"d"},
+		},
+		{
+			name: "removed node",
+			to: model.TreeResponse{
+				Nodes: []model.Node{node("root", "root", nil), node("a", "a", nil), node("b", "b", strPtr("なぜ？"))},
+				Edges: []model.Edge{edge("root", "", model.RelationNeutral, 0), edge("a", "root", model.RelationWhy, 0), edge("b", "root", model.RelationHow, 1)},
+			},
+			wantRemoved: []string{"c"},
+		},
+		{
+			name: "changed content and question",
+			to: model.TreeResponse{
+				Nodes: []model.Node{node("root", "root", nil), node("a", "a2", nil), node("b", "b", nil), node("c", "c", nil)},
+				Edges: from.Edges,
+			},
+			wantChanged: []string{"a", "b"},
+		},
+		{
+			name: "status change alone is not a content change",
+			to: model.TreeResponse{
+				Nodes: []model.Node{node("root", "root", nil), {ID: ids["a"], Content: "a", Status: model.NodeStatusDone}, node("b", "b", strPtr("なぜ？")), node("c", "c", nil)},
+				Edges: from.Edges,
+			},
+		},
+		{
+			name: "moved and reordered nodes",
+			to: model.TreeResponse{
+				Nodes: from.Nodes,
+				Edges: []model.Edge{edge("root", "", model.RelationNeutral, 0), edge("a", "root", model.RelationWhy, 1), edge("b", "root", model.RelationHow, 0), edge("c", "a", model.RelationWhat, 0)},
+			},
+			wantEdges: []string{"a", "b", "c"},
+		},
+		{
+			name: "changed relation",
+			to: model.TreeResponse{
+				Nodes: from.Nodes,
+				Edges: []model.Edge{edge("root", "", model.RelationNeutral, 0), edge("a", "root", model.RelationConcrete, 0), edge("b", "root", model.RelationHow, 1), edge("c", "root", model.RelationWhat, 2)},
+			},
+			wantEdges: []string{"a"},
+		},
+	}
+
+	names := make(map[uuid.UUID]string, len(ids))
+	for name, id := range ids {
+		names[id] = name
+	}
+	sorted := func(list []string) []string {
+		sort.Strings(list)
+		return list
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffTrees(from, tt.to)
+
+			added, removed, changed, edges := []string{}, []string{}, []string{}, []string{}
+			for _, n := range diff.AddedNodes {
+				added = append(added, names[n.ID])
+			}
+			for _, n := range diff.RemovedNodes {
+				removed = append(removed, names[n.ID])
+			}
+			for _, d := range diff.ChangedNodes {
+				changed = append(changed, names[d.NodeID])
+				if d.Before.ID != d.NodeID || d.After.ID != d.NodeID {
+					t.Errorf("node diff for %s has mismatched before/after", names[d.NodeID])
+				}
+			}
+			for _, d := range diff.ChangedEdges {
+				edges = append(edges, names[d.ChildNodeID])
+			}
+
+			for _, check := range []struct {
+				field     string
+				got, want []string
+			}{
+				{"AddedNodes", added, tt.wantAdded},
+				{"RemovedNodes", removed, tt.wantRemoved},
+				{"ChangedNodes", changed, tt.wantChanged},
+				{"ChangedEdges", edges, tt.wantEdges},
+			} {
+				want := check.want
+				if want == nil {
+					want = []string{}
+				}
+				if !reflect.DeepEqual(sorted(check.got), sorted(want)) {
+					t.Errorf("%s = %v, want %v", check.field, check.got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type TagService struct {
+	tagRepo repository.TagRepository
+	uow     repository.UnitOfWork
+	broker  *events.Broker
+}
+
+func NewTagService(tagRepo repository.TagRepository, uow repository.UnitOfWork, broker *events.Broker) *TagService {
+	return &TagService{
+		tagRepo: tagRepo,
+		uow:     uow,
+		broker:  broker,
+	}
+}
+
+func (s *TagService) ListTags(ctx context.Context, projectID uuid.UUID) ([]model.Tag, error) {
+	tags, err := s.tagRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []model.Tag{}
+	}
+	return tags, nil
+}
+
+func (s *TagService) CreateTag(ctx context.Context, projectID uuid.UUID, req model.CreateTagRequest) (*model.Tag, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return nil, ErrTagNameRequired
+	}
+
+	var tag *model.Tag
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, nil); err != nil {
+			return err
+		}
+		if err := checkTagNameAvailable(ctx, repos, projectID, nil, req.Name); err != nil {
+			return err
+		}
+		var err error
+		if tag, err = repos.Tags.Create(ctx, projectID, req); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeTagCreated, model.TagEvent{Tag: *tag})
+	return tag, nil
+}
+
+func (s *TagService) UpdateTag(ctx context.Context, projectID, tagID uuid.UUID, req model.UpdateTagRequest) (*model.Tag, error) {
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, ErrTagNameRequired
+		}
+		req.Name = &name
+	}
+
+	var tag *model.Tag
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, nil); err != nil {
+			return err
+		}
+		if _, err := findTag(ctx, repos, projectID, tagID); err != nil {
+			return err
+		}
+		if req.Name != nil {
+			if err := checkTagNameAvailable(ctx, repos, projectID, &tagID, *req.Name); err != nil {
+				return err
+			}
+		}
+		if err := repos.Tags.Update(ctx, tagID, req); err != nil {
+			return err
+		}
+		var err error
+		if tag, err = findTag(ctx, repos, projectID, tagID); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeTagUpdated, model.TagEvent{Tag: *tag})
+	return tag, nil
+}
+
+// DeleteTag はタグを削除し、付いていたノードからも外します
+func (s *TagService) DeleteTag(ctx context.Context, projectID, tagID uuid.UUID) error {
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if err := lockProjectRevision(ctx, repos, projectID, nil); err != nil {
+			return err
+		}
+		if _, err := findTag(ctx, repos, projectID, tagID); err != nil {
+			return err
+		}
+		if err := repos.Tags.Delete(ctx, tagID); err != nil {
+			return err
+		}
+		var err error
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeTagDeleted, model.TagRefEvent{TagID: tagID})
+	return nil
+}
+
+// SetNodeTags はノードのタグを置き換えます。プロジェクトにないタグが含まれる場合は ErrTagNotFound を返します
+func (s *TagService) SetNodeTags(ctx context.Context, projectID, nodeID uuid.UUID, req model.SetNodeTagsRequest) ([]uuid.UUID, error) {
+	tagIDs := make([]uuid.UUID, 0, len(req.TagIDs))
+	seen := make(map[uuid.UUID]bool, len(req.TagIDs))
+	for _, id := range req.TagIDs {
+		if !seen[id] {
+			seen[id] = true
+			tagIDs = append(tagIDs, id)
+		}
+	}
+
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		if _, err := lockNode(ctx, repos, projectID, nodeID, nil); err != nil {
+			return err
+		}
+		tags, err := repos.Tags.ListByProjectID(ctx, projectID)
+		if err != nil {
+			return err
+		}
+		known := make(map[uuid.UUID]bool, len(tags))
+		for _, tag := range tags {
+			known[tag.ID] = true
+		}
+		for _, id := range tagIDs {
+			if !known[id] {
+				return ErrTagNotFound
+			}
+		}
+		if err := repos.Tags.SetNodeTags(ctx, projectID, nodeID, tagIDs); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeTagsUpdated, model.NodeTagsEvent{NodeID: nodeID, TagIDs: tagIDs})
+	return tagIDs, nil
+}
+
+func findTag(ctx context.Context, repos repository.Repositories, projectID, tagID uuid.UUID) (*model.Tag, error) {
+	tag, err := repos.Tags.GetByID(ctx, tagID)
+	if err != nil {
+		return nil, err
+	}
+	if tag == nil || tag.ProjectID != projectID {
+		return nil, ErrTagNotFound
+	}
+	return tag, nil
+}
+
+// checkTagNameAvailable はプロジェクト内で同じ名前（大文字・小文字を区別しない）のタグがないことを確認します
+func checkTagNameAvailable(ctx context.Context, repos repository.Repositories, projectID uuid.UUID, exceptID *uuid.UUID, name string) error {
+	tags, err := repos.Tags.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if exceptID != nil && tag.ID == *exceptID {
+			continue
+		}
+		if strings.EqualFold(tag.Name, name) {
+			return ErrTagNameTaken
+		}
+	}
+	return nil
+}
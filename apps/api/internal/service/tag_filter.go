@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+// filterTreeByTags はタグの付いたノードとその祖先だけを残します（タグ定義の一覧はそのまま返します）
+// tags にはタグのIDまたは名前（大文字・小文字を区別しない）を指定でき、どれか1つが付いていれば一致とみなします
+func filterTreeByTags(tree *model.TreeResponse, tags []string) {
+	wanted := make(map[uuid.UUID]bool)
+	for _, tag := range tree.Tags {
+		for _, t := range tags {
+			if t == tag.ID.String() || strings.EqualFold(t, tag.Name) {
+				wanted[tag.ID] = true
+			}
+		}
+	}
+
+	parents := make(map[uuid.UUID]uuid.UUID, len(tree.Edges))
+	for _, edge := range tree.Edges {
+		if edge.ParentNodeID != nil {
+			parents[edge.ChildNodeID] = *edge.ParentNodeID
+		}
+	}
+
+	keep := make(map[uuid.UUID]bool)
+	for _, node := range tree.Nodes {
+		matched := false
+		for _, id := range node.TagIDs {
+			if wanted[id] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		// 祖先をたどり、すでに残すことが決まったノードに着いたら打ち切る
+		for id, ok := node.ID, true; ok && !keep[id]; id, ok = parents[id] {
+			keep[id] = true
+		}
+	}
+
+	nodes := []model.Node{}
+	for _, node := range tree.Nodes {
+		if keep[node.ID] {
+			nodes = append(nodes, node)
+		}
+	}
+	edges := []model.Edge{}
+	for _, edge := range tree.Edges {
+		if keep[edge.ChildNodeID] {
+			edges = append(edges, edge)
+		}
+	}
+	tree.Nodes = nodes
+	tree.Edges = edges
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestFilterTreeByTags(t *testing.T) {
+	urgent := model.Tag{ID: uuid.New(), Name: "Urgent"}
+	later := model.Tag{ID: uuid.New(), Name: "あとで"}
+	unused := model.Tag{ID: uuid.New(), Name: "未使用"}
+
+	// root ─ a ─ a1(urgent) ─ a1x
+	//      │   └ a2(あとで)
+	//      └ b ─ b1(urgent, あとで)
+	//      └ c
+	tagsByNode := map[string][]uuid.UUID{
+		"a1": {urgent.ID},
+		"a2": {later.ID},
+		"b1": {urgent.ID, later.ID},
+	}
+	parents := []struct{ name, parent string }{
+		{"root", ""}, {"a", "root"}, {"a1", "a"}, {"a1x", "a1"}, {"a2", "a"}, {"b", "root"}, {"b1", "b"}, {"c", "root"},
+	}
+	newTree := func() (*model.TreeResponse, map[uuid.UUID]string) {
+		tree := &model.TreeResponse{Tags: []model.Tag{urgent, later, unused}}
+		ids := map[string]uuid.UUID{}
+		names := map[uuid.UUID]string{}
+		for i, entry := range parents {
+			id := uuid.New()
+			ids[entry.name], names[id] = id, entry.name
+			tree.Nodes = append(tree.Nodes, model.Node{ID: id, Content: entry.name, TagIDs: tagsByNode[entry.name]})
+			edge := model.Edge{ChildNodeID: id, Relation: model.RelationNeutral, OrderIndex: i}
+			if entry.parent != "" {
+				parentID := ids[entry.parent]
+				edge.ParentNodeID = &parentID
+			}
+			tree.Edges = append(tree.Edges, edge)
+		}
+		return tree, names
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "by name keeps ancestors but not descendants", tags: []string{"Urgent"}, want: []string{"a", "a1", "b", "b1", "root"}},
+		{name: "name is case-insensitive", tags: []string{"uRGENT"}, want: []string{"a", "a1", "b", "b1", "root"}},
+		{name: "by id", tags: []string{later.ID.String()}, want: []string{"a", "a2", "b", "b1", "root"}},
+		{name: "several tags match any of them", tags: []string{"あとで", urgent.ID.String()}, want: []string{"a", "a1", "a2", "b", "b1", "root"}},
+		{name: "tag without nodes", tags: []string{"未使用"}, want: []string{}},
+		{name: "unknown tag", tags: []string{"存在しない", uuid.NewString()}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree, names := newTree()
+			filterTreeByTags(tree, tt.tags)
+
+			got := []string{}
+			kept := make(map[uuid.UUID]bool, len(tree.Nodes))
+			for _, node := range tree.Nodes {
+				got = append(got, names[node.ID])
+				kept[node.ID] = true
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nodes = %v, want %v", got, tt.want)
+			}
+
+			if len(tree.Edges) != len(tree.Nodes) {
+				t.Errorf("got %d edges for %d nodes", len(tree.Edges), len(tree.Nodes))
+			}
+			for _, edge := range tree.Edges {
+				if !kept[edge.ChildNodeID] || (edge.ParentNodeID != nil && !kept[*edge.ParentNodeID]) {
+					t.Errorf("edge to %s references a node that was filtered out", names[edge.ChildNodeID])
+				}
+			}
+			if len(tree.Tags) != 3 {
+				t.Errorf("tag definitions were filtered: %v", tree.Tags)
+			}
+		})
+	}
+}
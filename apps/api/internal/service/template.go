@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+// builtinTemplatesJSON はサーバーに同梱する組み込みテンプレートです
+//
+//go:embed templates/builtin.json
+var builtinTemplatesJSON []byte
+
+// builtinTemplates は起動時に読み込んだ組み込みテンプレートです（JSON が壊れていれば起動時に panic します）
+var builtinTemplates = mustLoadBuiltinTemplates()
+
+// templateVariablePattern は {{期限}} のようなプレースホルダーに一致します（前後の空白は名前に含めず、空白だけの {{ }} には一致しません）
+var templateVariablePattern = regexp.MustCompile(`\{\{\s*([^{}]*?[^{}\s])\s*\}\}`)
+
+type TemplateService struct {
+	templateRepo repository.TemplateRepository
+	uow          repository.UnitOfWork
+}
+
+func NewTemplateService(templateRepo repository.TemplateRepository, uow repository.UnitOfWork) *TemplateService {
+	return &TemplateService{
+		templateRepo: templateRepo,
+		uow:          uow,
+	}
+}
+
+// ListTemplates は組み込みテンプレートとユーザーが保存したテンプレートを返します（ノードは含めません）
+func (s *TemplateService) ListTemplates(ctx context.Context, userID uuid.UUID) ([]model.Template, error) {
+	saved, err := s.templateRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]model.Template, 0, len(builtinTemplates)+len(saved))
+	for _, template := range append(append([]model.Template{}, builtinTemplates...), saved...) {
+		template = describeTemplate(template)
+		template.Nodes = nil
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+// GetTemplate は組み込みテンプレートか、ユーザー自身が保存したテンプレートをノード付きで返します
+func (s *TemplateService) GetTemplate(ctx context.Context, userID, templateID uuid.UUID) (*model.Template, error) {
+	template, err := s.findTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	described := describeTemplate(*template)
+	return &described, nil
+}
+
+// CreateTemplate はプロジェクトの現在のツリーを、状態や期日を除いた構造だけのテンプレートとして保存します
+func (s *TemplateService) CreateTemplate(ctx context.Context, userID uuid.UUID, req model.CreateTemplateRequest) (*model.Template, error) {
+	// GetTree と同じく、ノードとエッジを1つのスナップショットから読む
+	var tree *model.TreeResponse
+	err := s.uow.Read(ctx, func(repos repository.Repositories) error {
+		var err error
+		tree, err = loadTree(ctx, repos, req.ProjectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := templateNodes(export.BuildOutline(tree.Nodes, tree.Edges))
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("%w: project has no nodes", ErrInvalidTemplate)
+	}
+	if n := countTemplateNodes(nodes); n > maxImportNodes {
+		return nil, fmt.Errorf("%w: too many nodes (max %d)", ErrInvalidTemplate, maxImportNodes)
+	}
+
+	title := tree.Project.Title
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("%w: title must not be blank", ErrInvalidTemplate)
+	}
+	description := req.Description
+	if description == nil {
+		description = tree.Project.Description
+	}
+
+	template, err := s.templateRepo.Create(ctx, userID, title, description, nodes)
+	if err != nil {
+		return nil, err
+	}
+	described := describeTemplate(*template)
+	return &described, nil
+}
+
+// DeleteTemplate はユーザー自身が保存したテンプレートを削除します
+func (s *TemplateService) DeleteTemplate(ctx context.Context, userID, templateID uuid.UUID) error {
+	if builtinTemplate(templateID) != nil {
+		return ErrTemplateIsBuiltin
+	}
+	deleted, err := s.templateRepo.Delete(ctx, userID, templateID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrTemplateNotFound
+	}
+	return nil
+}
+
+// CreateProjectFromTemplate はテンプレートの変数を埋め、そのツリーを持つ新しいプロジェクトを1つのトランザクションで作成します
+func (s *TemplateService) CreateProjectFromTemplate(ctx context.Context, userID, templateID uuid.UUID, req model.CreateProjectFromTemplateRequest) (*model.Project, error) {
+	template, err := s.findTemplate(ctx, userID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range templateVariables(template.Nodes) {
+		if strings.TrimSpace(req.Variables[name]) == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing values for variables: %s", ErrInvalidTemplate, strings.Join(missing, ", "))
+	}
+
+	roots := fillTemplate(template.Nodes, req.Variables)
+	title := trimToRunes(strings.TrimSpace(roots[0].Node.Content), 20)
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+	}
+	if n := utf8.RuneCountInString(title); n < 3 || n > 20 {
+		return nil, fmt.Errorf("%w: title must be between 3 and 20 characters", ErrInvalidTemplate)
+	}
+	description := req.Description
+	if description == nil {
+		description = template.Description
+	}
+
+	nodes, edges := flattenOutline(roots, nil, 0)
+	if err := validateImportedNodes(nodes, edges); err != nil {
+		return nil, err
+	}
+
+	// 複製と同じく、プロジェクト（オーナーの登録を含む）とツリーを1つのトランザクションで作成する
+	var project *model.Project
+	err = s.uow.Do(ctx, func(repos repository.Repositories) error {
+		var err error
+		project, err = repos.Projects.Create(ctx, userID, model.CreateProjectRequest{Title: title, Description: description})
+		if err != nil {
+			return err
+		}
+		if _, _, err := repos.Nodes.CreateSubtree(ctx, project.ID, nodes, edges); err != nil {
+			return err
+		}
+
+		// ノードとエッジの作成で進んだリビジョンを返す
+		project, err = repos.Projects.GetByID(ctx, project.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// findTemplate は組み込みテンプレートか、userID が保存したテンプレートを返します
+// 他のユーザーのテンプレートは存在しないものとして扱います
+func (s *TemplateService) findTemplate(ctx context.Context, userID, templateID uuid.UUID) (*model.Template, error) {
+	if template := builtinTemplate(templateID); template != nil {
+		return template, nil
+	}
+	template, err := s.templateRepo.GetByID(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil || template.UserID == nil || *template.UserID != userID {
+		return nil, ErrTemplateNotFound
+	}
+	return template, nil
+}
+
+func builtinTemplate(templateID uuid.UUID) *model.Template {
+	for i := range builtinTemplates {
+		if builtinTemplates[i].ID == templateID {
+			template := builtinTemplates[i]
+			return &template
+		}
+	}
+	return nil
+}
+
+func mustLoadBuiltinTemplates() []model.Template {
+	var templates []model.Template
+	if err := json.Unmarshal(builtinTemplatesJSON, &templates); err != nil {
+		panic(fmt.Sprintf("failed to load built-in templates: %v", err))
+	}
+	for i := range templates {
+		templates[i].Builtin = true
+	}
+	return templates
+}
+
+// describeTemplate はノードから変数の一覧とノード数を埋めます
+func describeTemplate(template model.Template) model.Template {
+	template.Variables = templateVariables(template.Nodes)
+	template.NodeCount = countTemplateNodes(template.Nodes)
+	return template
+}
+
+// templateNodes はアウトラインを状態・期日・IDを持たないテンプレートのノードに変換します
+func templateNodes(outline []*export.OutlineNode) []model.TemplateNode {
+	nodes := make([]model.TemplateNode, 0, len(outline))
+	for _, item := range outline {
+		node := model.TemplateNode{
+			Content:       item.Node.Content,
+			Question:      item.Node.Question,
+			Relation:      item.Edge.Relation,
+			RelationLabel: item.Edge.RelationLabel,
+			Weight:        item.Node.Weight,
+			Children:      templateNodes(item.Children),
+		}
+		if node.Relation == model.RelationNeutral {
+			node.Relation = ""
+		}
+		if item.Edge.ParentNodeID == nil {
+			// 最上位のノードの関係は作成時に neutral になる
+			node.Relation = ""
+			node.RelationLabel = nil
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// fillTemplate はテンプレートのノードの変数を values で置き換え、インポートと同じアウトラインに変換します
+func fillTemplate(nodes []model.TemplateNode, values map[string]string) []*export.OutlineNode {
+	fill := func(text string) string {
+		return templateVariablePattern.ReplaceAllStringFunc(text, func(match string) string {
+			name := templateVariablePattern.FindStringSubmatch(match)[1]
+			return strings.TrimSpace(values[name])
+		})
+	}
+	fillPtr := func(text *string) *string {
+		if text == nil {
+			return nil
+		}
+		filled := fill(*text)
+		return &filled
+	}
+
+	outline := make([]*export.OutlineNode, 0, len(nodes))
+	for _, node := range nodes {
+		outline = append(outline, &export.OutlineNode{
+			Node: model.Node{
+				Content:  fill(node.Content),
+				Question: fillPtr(node.Question),
+				Status:   model.NodeStatusTodo,
+				Weight:   node.Weight,
+			},
+			Edge: model.Edge{
+				Relation:      node.Relation,
+				RelationLabel: fillPtr(node.RelationLabel),
+			},
+			Children: fillTemplate(node.Children, values),
+		})
+	}
+	return outline
+}
+
+// templateVariables はノードに含まれる変数名を、最初に現れた順に重複なく返します
+func templateVariables(nodes []model.TemplateNode) []string {
+	variables := []string{}
+	seen := make(map[string]struct{})
+	collect := func(text *string) {
+		if text == nil {
+			return
+		}
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(*text, -1) {
+			if _, ok := seen[match[1]]; ok {
+				continue
+			}
+			seen[match[1]] = struct{}{}
+			variables = append(variables, match[1])
+		}
+	}
+
+	var walk func(list []model.TemplateNode)
+	walk = func(list []model.TemplateNode) {
+		for _, node := range list {
+			collect(&node.Content)
+			collect(node.Question)
+			collect(node.RelationLabel)
+			walk(node.Children)
+		}
+	}
+	walk(nodes)
+	return variables
+}
+
+func countTemplateNodes(nodes []model.TemplateNode) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		count += countTemplateNodes(node.Children)
+	}
+	return count
+}
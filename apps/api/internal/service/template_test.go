@@ -0,0 +1,164 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/export"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+)
+
+func TestTemplateVariablePattern(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "変数なし", want: nil},
+		{text: "{{期限}}までに", want: []string{"期限"}},
+		{text: "{{ 目標 }}を{{期限}}に", want: []string{"目標", "期限"}},
+		{text: "{{試験 名}}", want: []string{"試験 名"}},
+		{text: "{{}} と {{ }} は変数ではない", want: nil},
+		{text: "{{{入れ子}}}", want: []string{"入れ子"}},
+		{text: "{期限} や {{期限} は閉じていない", want: nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, match := range templateVariablePattern.FindAllStringSubmatch(tt.text, -1) {
+			got = append(got, match[1])
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("variables in %q = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTemplateVariables(t *testing.T) {
+	nodes := []model.TemplateNode{
+		{
+			Content:  "{{目標}}を{{期限}}までに達成する",
+			Question: strPtr("{{目標}}はなぜ大事？"),
+			Children: []model.TemplateNode{
+				{Content: "{{ 習慣 }}を続ける", RelationLabel: strPtr("{{手段}}")},
+				{Content: "変数なし"},
+			},
+		},
+	}
+
+	want := []string{"目標", "期限", "習慣", "手段"}
+	if got := templateVariables(nodes); !reflect.DeepEqual(got, want) {
+		t.Errorf("templateVariables() = %q, want %q", got, want)
+	}
+	if got := templateVariables(nil); got == nil || len(got) != 0 {
+		t.Errorf("templateVariables(nil) = %#v, want empty slice", got)
+	}
+}
+
+func TestFillTemplate(t *testing.T) {
+	weight := 2.0
+	nodes := []model.TemplateNode{
+		{
+			Content:  "{{目標}}を{{ 期限 }}までに",
+			Question: strPtr("{{目標}}の理由は？"),
+			Children: []model.TemplateNode{
+				{Content: "{{未指定}}を確認", Relation: model.RelationCustom, RelationLabel: strPtr("{{目標}}のため"), Weight: &weight},
+				{Content: "そのまま", Relation: model.RelationHow},
+			},
+		},
+	}
+	values := map[string]string{"目標": " TOEIC 800点 ", "期限": "3月"}
+
+	roots := fillTemplate(nodes, values)
+	if len(roots) != 1 || len(roots[0].Children) != 2 {
+		t.Fatalf("fillTemplate() returned unexpected shape")
+	}
+
+	tests := []struct {
+		name string
+		node *export.OutlineNode
+		want export.OutlineNode
+	}{
+		{
+			name: "root",
+			node: roots[0],
+			want: export.OutlineNode{
+				Node: model.Node{Content: "TOEIC 800点を3月までに", Question: strPtr("TOEIC 800点の理由は？"), Status: model.NodeStatusTodo},
+			},
+		},
+		{
+			name: "missing value becomes empty",
+			node: roots[0].Children[0],
+			want: export.OutlineNode{
+				Node: model.Node{Content: "を確認", Status: model.NodeStatusTodo, Weight: &weight},
+				Edge: model.Edge{Relation: model.RelationCustom, RelationLabel: strPtr("TOEIC 800点のため")},
+			},
+		},
+		{
+			name: "no variables",
+			node: roots[0].Children[1],
+			want: export.OutlineNode{
+				Node: model.Node{Content: "そのまま", Status: model.NodeStatusTodo},
+				Edge: model.Edge{Relation: model.RelationHow},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := *tt.node
+			got.Children = nil
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fillTemplate() node = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateNodesDropRootRelation(t *testing.T) {
+	root, child := uuid.New(), uuid.New()
+	outline := export.BuildOutline(
+		[]model.Node{{ID: root, Content: "根", Status: model.NodeStatusDone}, {ID: child, Content: "子"}},
+		[]model.Edge{
+			{ChildNodeID: root, Relation: model.RelationWhy, RelationLabel: strPtr("無視される")},
+			{ParentNodeID: &root, ChildNodeID: child, Relation: model.RelationNeutral},
+		},
+	)
+
+	want := []model.TemplateNode{{Content: "根", Children: []model.TemplateNode{{Content: "子", Children: []model.TemplateNode{}}}}}
+	if got := templateNodes(outline); !reflect.DeepEqual(got, want) {
+		t.Errorf("templateNodes() = %+v, want %+v", got, want)
+	}
+}
+
+// TestBuiltinTemplates は組み込みテンプレートが、変数を埋めるとインポートと同じ検証を通ることを確かめます
+func TestBuiltinTemplates(t *testing.T) {
+	if len(builtinTemplates) == 0 {
+		t.Fatal("no built-in templates")
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, template := range builtinTemplates {
+		t.Run(template.Title, func(t *testing.T) {
+			if template.ID == uuid.Nil || seen[template.ID] {
+				t.Errorf("ID %s is empty or duplicated", template.ID)
+			}
+			seen[template.ID] = true
+			if !template.Builtin {
+				t.Error("Builtin is false")
+			}
+
+			values := make(map[string]string)
+			for _, name := range templateVariables(template.Nodes) {
+				values[name] = strings.Repeat("x", 10)
+			}
+			nodes, edges := flattenOutline(fillTemplate(template.Nodes, values), nil, 0)
+			if len(nodes) == 0 {
+				t.Fatal("template has no nodes")
+			}
+			if err := validateImportedNodes(nodes, edges); err != nil {
+				t.Errorf("validateImportedNodes() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// secretTokenBytes は共有リンクやフィードのトークンの乱数部分のバイト数です
+const secretTokenBytes = 32
+
+// newSecretToken は URL にそのまま載せられるランダムなトークンを生成します
+func newSecretToken() (string, error) {
+	buf := make([]byte, secretTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashToken はトークンを保存・照合するための SHA-256 ハッシュを返します
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
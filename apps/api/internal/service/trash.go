@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mokuhyo-driven-test/api/internal/events"
+	"github.com/mokuhyo-driven-test/api/internal/model"
+	"github.com/mokuhyo-driven-test/api/internal/repository"
+)
+
+type TrashService struct {
+	nodeRepo  repository.NodeRepository
+	uow       repository.UnitOfWork
+	broker    *events.Broker
+	retention time.Duration
+}
+
+func NewTrashService(nodeRepo repository.NodeRepository, uow repository.UnitOfWork, broker *events.Broker, retention time.Duration) *TrashService {
+	return &TrashService{
+		nodeRepo:  nodeRepo,
+		uow:       uow,
+		broker:    broker,
+		retention: retention,
+	}
+}
+
+func (s *TrashService) Retention() time.Duration {
+	return s.retention
+}
+
+func (s *TrashService) ListTrash(ctx context.Context, projectID uuid.UUID) ([]model.TrashEntry, error) {
+	entries, err := s.nodeRepo.ListDeleted(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []model.TrashEntry{}
+	}
+	return entries, nil
+}
+
+// RestoreNode は論理削除されたノードを、同じ操作で削除された子孫とともに復元します
+func (s *TrashService) RestoreNode(ctx context.Context, userID, projectID, nodeID uuid.UUID) error {
+	// 親の確認と復元の間に親が削除されないよう、同じトランザクションで行う
+	var revision int64
+	err := s.uow.Do(ctx, func(repos repository.Repositories) error {
+		node, err := repos.Nodes.GetByIDIncludingDeleted(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		if node == nil || node.ProjectID != projectID {
+			return ErrNodeNotFound
+		}
+		if node.DeletedAt == nil {
+			return ErrNodeNotDeleted
+		}
+
+		edge, err := repos.Edges.GetByChildNodeID(ctx, nodeID)
+		if err != nil {
+			return err
+		}
+		if edge != nil && edge.ParentNodeID != nil {
+			parent, err := repos.Nodes.GetByID(ctx, *edge.ParentNodeID)
+			if err != nil {
+				return err
+			}
+			if parent == nil {
+				return ErrParentIsDeleted
+			}
+		}
+
+		if err := repos.Nodes.RestoreWithDescendants(ctx, projectID, nodeID); err != nil {
+			return err
+		}
+		var after model.OperationValue
+		if edge != nil {
+			siblings, err := childOrder(ctx, repos, projectID, edge.ParentNodeID)
+			if err != nil {
+				return err
+			}
+			after = model.OperationValue{ParentNodeID: edge.ParentNodeID, OrderedChildNodeIDs: siblings}
+		}
+		if err := recordOperation(ctx, repos, projectID, userID, model.OperationNodeRestore, []uuid.UUID{nodeID}, model.OperationValue{}, after); err != nil {
+			return err
+		}
+		revision, err = currentRevision(ctx, repos, projectID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	publishEvent(ctx, s.broker, projectID, revision, events.TypeNodeRestored, model.NodeRefEvent{NodeID: nodeID})
+	return nil
+}
+
+// PurgeTrash はプロジェクトのゴミ箱から olderThan より古い項目を物理削除します
+func (s *TrashService) PurgeTrash(ctx context.Context, projectID uuid.UUID, olderThan time.Duration) (int64, error) {
+	return s.nodeRepo.PurgeDeleted(ctx, &projectID, time.Now().Add(-olderThan))
+}
+
+// RunPurgeLoop は保持期間を過ぎたゴミ箱の項目を interval ごとに全プロジェクトから物理削除します
+// ctx がキャンセルされるまでブロックします
+func (s *TrashService) RunPurgeLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.nodeRepo.PurgeDeleted(ctx, nil, time.Now().Add(-s.retention))
+		if err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("Trash purge removed %d nodes", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,13 @@
+-- Enable RLS on snapshots now that the API reads and writes them
+
+alter table snapshots enable row level security;
+
+create policy "snapshots_select_own" on snapshots
+for select using (exists (
+  select 1 from projects p where p.id = snapshots.project_id and p.user_id = auth.uid()
+));
+
+create policy "snapshots_insert_own" on snapshots
+for insert with check (exists (
+  select 1 from projects p where p.id = snapshots.project_id and p.user_id = auth.uid()
+));
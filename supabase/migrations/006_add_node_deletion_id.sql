@@ -0,0 +1,18 @@
+-- Group soft-deleted nodes by the delete operation that removed them
+ALTER TABLE nodes
+ADD COLUMN IF NOT EXISTS deletion_id uuid;
+
+CREATE INDEX IF NOT EXISTS nodes_deletion_id_idx ON nodes(deletion_id) WHERE deletion_id IS NOT NULL;
+
+-- Backfill: nodes deleted by the same statement share the same deleted_at
+UPDATE nodes n
+SET deletion_id = g.deletion_id
+FROM (
+  SELECT project_id, deleted_at, gen_random_uuid() AS deletion_id
+  FROM nodes
+  WHERE deleted_at IS NOT NULL
+  GROUP BY project_id, deleted_at
+) g
+WHERE n.project_id = g.project_id
+  AND n.deleted_at = g.deleted_at
+  AND n.deletion_id IS NULL;
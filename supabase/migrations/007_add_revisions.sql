@@ -0,0 +1,28 @@
+-- Optimistic concurrency: per-node version and per-project revision (exposed as ETag)
+ALTER TABLE nodes
+ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE projects
+ADD COLUMN IF NOT EXISTS revision bigint NOT NULL DEFAULT 1;
+
+-- Any change to a project's nodes or edges advances the project revision
+CREATE OR REPLACE FUNCTION bump_project_revision() RETURNS trigger AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    UPDATE projects SET revision = revision + 1 WHERE id = OLD.project_id;
+  ELSE
+    UPDATE projects SET revision = revision + 1 WHERE id = NEW.project_id;
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS nodes_bump_project_revision ON nodes;
+CREATE TRIGGER nodes_bump_project_revision
+AFTER INSERT OR UPDATE OR DELETE ON nodes
+FOR EACH ROW EXECUTE FUNCTION bump_project_revision();
+
+DROP TRIGGER IF EXISTS edges_bump_project_revision ON edges;
+CREATE TRIGGER edges_bump_project_revision
+AFTER INSERT OR UPDATE OR DELETE ON edges
+FOR EACH ROW EXECUTE FUNCTION bump_project_revision();
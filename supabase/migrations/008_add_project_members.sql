@@ -0,0 +1,23 @@
+-- Project sharing: members with roles (owner / editor / viewer)
+
+create table if not exists project_members (
+  project_id uuid not null references projects(id) on delete cascade,
+  user_id uuid not null references users(id) on delete cascade,
+  role text not null check (role in ('owner', 'editor', 'viewer')),
+  invited_by uuid references users(id) on delete set null,
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now(),
+  primary key (project_id, user_id)
+);
+
+create index if not exists project_members_user_id_idx on project_members(user_id);
+
+-- Backfill: every existing project owner becomes an owner member
+insert into project_members (project_id, user_id, role)
+select id, user_id, 'owner' from projects
+on conflict (project_id, user_id) do nothing;
+
+alter table project_members enable row level security;
+
+create policy "project_members_select_own" on project_members
+for select using (auth.uid() = user_id);
@@ -0,0 +1,17 @@
+-- Public read-only share links (only the SHA-256 hash of the token is stored)
+
+create table if not exists share_links (
+  id uuid primary key default gen_random_uuid(),
+  project_id uuid not null references projects(id) on delete cascade,
+  token_hash text not null unique,
+  label text,
+  created_by uuid references users(id) on delete set null,
+  expires_at timestamptz,
+  revoked_at timestamptz,
+  created_at timestamptz not null default now()
+);
+
+create index if not exists share_links_project_id_idx on share_links(project_id);
+
+-- 公開ツリーはAPI経由（サービスロール）でのみ参照するため、クライアントからは読めないようにする
+alter table share_links enable row level security;
@@ -0,0 +1,10 @@
+-- Goal tracking on nodes: status, optional due date and optional weight for progress roll-up
+
+alter table nodes
+  add column if not exists status text not null default 'todo'
+    check (status in ('todo', 'doing', 'done', 'dropped')),
+  add column if not exists due_date date,
+  add column if not exists weight double precision check (weight > 0);
+
+create index if not exists nodes_due_date_idx on nodes(project_id, due_date)
+  where due_date is not null and deleted_at is null;
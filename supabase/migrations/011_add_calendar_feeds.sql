@@ -0,0 +1,9 @@
+-- Per-user iCalendar feed tokens (only the SHA-256 hash of the token is stored)
+
+create table if not exists calendar_feeds (
+  user_id uuid primary key references users(id) on delete cascade,
+  token_hash text not null unique,
+  created_at timestamptz not null default now()
+);
+
+alter table calendar_feeds enable row level security;
@@ -0,0 +1,9 @@
+-- Full-text search: trigram indexes so that substring (ILIKE) matching works for Japanese text
+-- without whitespace tokenization. Patterns shorter than 3 characters still match, but without the index.
+
+create extension if not exists pg_trgm;
+
+create index if not exists nodes_content_trgm_idx on nodes using gin (content gin_trgm_ops);
+create index if not exists nodes_question_trgm_idx on nodes using gin (question gin_trgm_ops);
+create index if not exists projects_title_trgm_idx on projects using gin (title gin_trgm_ops);
+create index if not exists projects_description_trgm_idx on projects using gin (description gin_trgm_ops);
@@ -0,0 +1,35 @@
+-- Tags: per-project tag definitions and many-to-many node tags
+
+create table if not exists tags (
+  id uuid primary key default gen_random_uuid(),
+  project_id uuid not null references projects(id) on delete cascade,
+  name text not null check (char_length(name) between 1 and 30),
+  color text not null check (color ~ '^#[0-9a-fA-F]{6}$'),
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+create unique index if not exists tags_project_name_idx on tags(project_id, lower(name));
+
+create table if not exists node_tags (
+  project_id uuid not null references projects(id) on delete cascade,
+  node_id uuid not null references nodes(id) on delete cascade,
+  tag_id uuid not null references tags(id) on delete cascade,
+  created_at timestamptz not null default now(),
+  primary key (node_id, tag_id)
+);
+create index if not exists node_tags_project_id_idx on node_tags(project_id);
+create index if not exists node_tags_tag_id_idx on node_tags(tag_id);
+
+-- Tags are part of the tree response, so changing them advances the project revision (ETag)
+drop trigger if exists tags_bump_project_revision on tags;
+create trigger tags_bump_project_revision
+after insert or update or delete on tags
+for each row execute function bump_project_revision();
+
+drop trigger if exists node_tags_bump_project_revision on node_tags;
+create trigger node_tags_bump_project_revision
+after insert or update or delete on node_tags
+for each row execute function bump_project_revision();
+
+alter table tags enable row level security;
+alter table node_tags enable row level security;
@@ -0,0 +1,19 @@
+-- Append-only operation log for per-user undo/redo
+-- Each row records one mutating action with the state before and after it.
+-- state: done (can be undone) / undone (can be redone) / discarded (no longer reachable)
+
+create table if not exists operations (
+  id uuid primary key default gen_random_uuid(),
+  seq bigint generated always as identity,
+  project_id uuid not null references projects(id) on delete cascade,
+  user_id uuid not null references users(id) on delete cascade,
+  kind text not null,
+  payload jsonb not null,
+  state text not null default 'done' check (state in ('done', 'undone', 'discarded')),
+  created_at timestamptz not null default now(),
+  updated_at timestamptz not null default now()
+);
+
+create index if not exists operations_cursor_idx on operations(project_id, user_id, state, seq);
+
+alter table operations enable row level security;
@@ -0,0 +1,20 @@
+-- Audit trail of security-relevant and data-changing events
+-- Rows are never updated. Project and node IDs are kept as plain values so the trail
+-- outlives the project (only deleting the actor's account removes their events).
+
+create table if not exists audit_events (
+  id uuid primary key default gen_random_uuid(),
+  seq bigint generated always as identity,
+  user_id uuid not null references users(id) on delete cascade,
+  action text not null,
+  project_id uuid,
+  node_id uuid,
+  metadata jsonb not null default '{}'::jsonb,
+  ip_address text not null default '',
+  user_agent text not null default '',
+  created_at timestamptz not null default now()
+);
+
+create index if not exists audit_events_user_seq_idx on audit_events(user_id, seq desc);
+
+alter table audit_events enable row level security;
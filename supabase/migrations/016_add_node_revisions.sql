@@ -0,0 +1,39 @@
+-- Per-node edit history: every insert or change of content/question keeps a copy of the text
+
+create table if not exists node_revisions (
+  id uuid primary key default gen_random_uuid(),
+  seq bigint generated always as identity,
+  node_id uuid not null references nodes(id) on delete cascade,
+  project_id uuid not null references projects(id) on delete cascade,
+  version bigint not null,
+  content text not null,
+  question text,
+  created_at timestamptz not null default now()
+);
+create index if not exists node_revisions_node_seq_idx on node_revisions(node_id, seq desc);
+
+create or replace function record_node_revision() returns trigger as $$
+begin
+  if TG_OP = 'UPDATE'
+     and NEW.content is not distinct from OLD.content
+     and NEW.question is not distinct from OLD.question then
+    return null;
+  end if;
+  insert into node_revisions (node_id, project_id, version, content, question)
+  values (NEW.id, NEW.project_id, NEW.version, NEW.content, NEW.question);
+  return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists nodes_record_revision on nodes;
+create trigger nodes_record_revision
+after insert or update of content, question on nodes
+for each row execute function record_node_revision();
+
+-- Existing nodes start their history from the current text
+insert into node_revisions (node_id, project_id, version, content, question, created_at)
+select n.id, n.project_id, n.version, n.content, n.question, n.updated_at
+from nodes n
+where not exists (select 1 from node_revisions r where r.node_id = n.id);
+
+alter table node_revisions enable row level security;
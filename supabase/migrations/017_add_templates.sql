@@ -0,0 +1,17 @@
+-- User-saved project templates
+-- The outline (content, question, relation and children) is stored as jsonb so a template
+-- stays usable after the project it was saved from is changed or deleted.
+-- Built-in templates are shipped with the server and are not stored here.
+
+create table if not exists templates (
+  id uuid primary key default gen_random_uuid(),
+  user_id uuid not null references users(id) on delete cascade,
+  title text not null check (char_length(title) between 1 and 50),
+  description text,
+  nodes jsonb not null default '[]'::jsonb,
+  created_at timestamptz not null default now()
+);
+
+create index if not exists templates_user_created_idx on templates(user_id, created_at desc);
+
+alter table templates enable row level security;
@@ -0,0 +1,9 @@
+-- Background question generation state
+-- 'pending' while a question is being generated for a newly created node; cleared when the
+-- question is saved. Kept on the node so every API instance reports the same pending nodes.
+
+alter table nodes add column if not exists question_status text
+  check (question_status in ('pending'));
+
+create index if not exists nodes_question_pending_idx on nodes(project_id)
+  where question_status is not null;